@@ -7,18 +7,105 @@ import (
 	"github.com/FrauElster/gopenApiToGraphQL/util"
 	"log"
 	"os"
+	"strings"
 )
 
 type opts struct {
-	oasFile string
-	gqlFile string
+	oasFile   string
+	gqlFile   string
+	parseOpts parser.Options
+}
+
+// mappingFlag is a repeatable flag of key=value pairs, e.g. "-scalar uuid=ID -scalar date-time=String"
+type mappingFlag func(key string, value string) error
+
+func (f mappingFlag) String() string {
+	return ""
+}
+
+func (f mappingFlag) Set(raw string) error {
+	key, value, found := strings.Cut(raw, "=")
+	if !found || key == "" {
+		return fmt.Errorf("expected key=value, got %q", raw)
+	}
+	return f(key, value)
+}
+
+// scalarMapping overrides the format to scalar mapping, an empty scalar removes the mapping
+func scalarMapping(scalars map[string]string) mappingFlag {
+	return func(format string, scalar string) error {
+		if scalar == "" {
+			delete(scalars, format)
+			return nil
+		}
+		scalars[format] = scalar
+		return nil
+	}
+}
+
+// rootMapping overrides which root type the operations of an HTTP method are added to
+func rootMapping(roots map[string]parser.OperationRoot) mappingFlag {
+	return func(method string, root string) error {
+		switch parser.OperationRoot(root) {
+		case parser.RootQuery, parser.RootMutation, parser.RootSkip:
+			roots[strings.ToUpper(method)] = parser.OperationRoot(root)
+			return nil
+		}
+		return fmt.Errorf("%q is no valid root, expected %s, %s or %s", root, parser.RootQuery, parser.RootMutation, parser.RootSkip)
+	}
+}
+
+// parameterMapping overrides the policy used for the parameters of a location
+func parameterMapping(policies map[string]parser.ParameterPolicy) mappingFlag {
+	return func(location string, policy string) error {
+		switch parser.ParameterPolicy(policy) {
+		case parser.PolicyArgument, parser.PolicyGroup, parser.PolicyHide:
+			policies[strings.ToLower(location)] = parser.ParameterPolicy(policy)
+			return nil
+		}
+		return fmt.Errorf("%q is no valid policy, expected %s, %s or %s", policy, parser.PolicyArgument, parser.PolicyGroup, parser.PolicyHide)
+	}
 }
 
 func parseFlags() (opts, error) {
 	// parse oas flag
 	oasFile := flag.String("oas", "", "the openapi spec file")
 	gqlRawFile := flag.String("gql", "", "the output file")
+	parseOpts := parser.DefaultOptions()
+	flag.BoolVar(&parseOpts.GenerateInterfaces, "interfaces", parseOpts.GenerateInterfaces, "generate interfaces for schemas extended via allOf")
+	flag.BoolVar(&parseOpts.ResultUnions, "result-unions", parseOpts.ResultUnions, "return a union of all documented responses, including errors")
+	flag.BoolVar(&parseOpts.ResponsePayloads, "response-payloads", parseOpts.ResponsePayloads, "wrap responses into a payload type with the body as data and the response headers")
+	flag.BoolVar(&parseOpts.Timestamp, "timestamp", parseOpts.Timestamp, "stamp the generation time into the GraphQL spec")
+	flag.Func("order", "order paths, schemas and properties by \"name\" or keep their \"source\" order (default \"name\")", func(order string) error {
+		switch parser.Ordering(order) {
+		case parser.OrderByName, parser.OrderBySource:
+			parseOpts.Ordering = parser.Ordering(order)
+			return nil
+		}
+		return fmt.Errorf("%q is no valid order, expected %s or %s", order, parser.OrderByName, parser.OrderBySource)
+	})
+	flag.Func("no-content", "return \"boolean\", \"void\" or a \"payload\" type from operations without response body (default \"boolean\")", func(noContent string) error {
+		switch parser.NoContent(noContent) {
+		case parser.NoContentBoolean, parser.NoContentVoid, parser.NoContentPayload:
+			parseOpts.NoContent = parser.NoContent(noContent)
+			return nil
+		}
+		return fmt.Errorf("%q is no valid representation, expected %s, %s or %s", noContent, parser.NoContentBoolean, parser.NoContentVoid, parser.NoContentPayload)
+	})
+	flag.Var(scalarMapping(parseOpts.Scalars), "scalar", "map an OpenAPI format to a GraphQL scalar, e.g. uuid=ID (repeatable)")
+	flag.Var(rootMapping(parseOpts.OperationRoots), "operation", "add the operations of an HTTP method to query, mutation or skip them, e.g. HEAD=query (repeatable)")
+	flag.Var(parameterMapping(parseOpts.ParameterPolicies), "parameter", "pass the parameters of a location as argument, group them or hide them, e.g. header=argument (repeatable)")
+	flag.Func("hide-header", "always hide a header parameter, in addition to the well known transport headers (repeatable)", func(header string) error {
+		parseOpts.HiddenHeaders = append(parseOpts.HiddenHeaders, header)
+		return nil
+	})
+	preferredMediaTypes := make([]string, 0)
+	flag.Func("media-type", "prefer a media type over the JSON ones, ranges like application/*+xml are allowed (repeatable)", func(mediaType string) error {
+		preferredMediaTypes = append(preferredMediaTypes, mediaType)
+		return nil
+	})
 	flag.Parse()
+	parseOpts.MediaTypes = append(preferredMediaTypes, parseOpts.MediaTypes...)
 
 	// check if set
 	if *oasFile == "" {
@@ -35,7 +122,7 @@ func parseFlags() (opts, error) {
 		return opts{}, err
 	}
 
-	return opts{oasFile: *oasFile, gqlFile: gqlFile}, nil
+	return opts{oasFile: *oasFile, gqlFile: gqlFile, parseOpts: parseOpts}, nil
 }
 
 func main() {
@@ -46,11 +133,15 @@ func main() {
 	}
 
 	// parse OAS to GraphQL
-	gqlSpec, err := parser.Parse(opts.oasFile)
+	gqlSpec, err := parser.ParseWithOptions(opts.oasFile, opts.parseOpts)
 	if err != nil {
 		log.Fatalf("parsing err: %s", err)
 	}
 
+	for _, diagnostic := range gqlSpec.Diagnostics {
+		log.Printf("warning: %s", diagnostic)
+	}
+
 	// write it to file
 	err = os.WriteFile(opts.gqlFile, []byte(gqlSpec.String()), 0644)
 	if err != nil {
@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"github.com/FrauElster/gopenApiToGraphQL/util"
+	"github.com/getkin/kin-openapi/openapi3"
+	"sort"
+)
+
+// converter holds the state of a single OpenAPI -> GraphQL conversion.
+// Everything that is generated on the fly while walking the document (e.g. input types) is registered here,
+// so that we can put it into the GqlSpec once we are done
+type converter struct {
+	doc  *openapi3.T
+	opts Options
+
+	// types are all GraphQL types, named ones as well as the ones declared for anonymous objects
+	types []GqlType
+	// hoisted maps anonymous object schemas to the name of the type declared for them
+	hoisted map[*openapi3.Schema]string
+
+	// inputs are all GraphQL input types generated so far, in the order they were generated in
+	inputs []*GqlInput
+	// inputNames maps schemas to the name of the input type generated for them
+	inputNames map[*openapi3.Schema]string
+
+	// enums are all GraphQL enums, enumNames maps schemas to the name of the enum declared for them
+	enums     []GqlEnum
+	enumNames map[*openapi3.Schema]string
+
+	// unions are all GraphQL unions, unionNames maps schemas to the name of the union (or scalar) declared for them
+	unions     []GqlUnion
+	unionNames map[*openapi3.Schema]string
+
+	// flattened maps allOf schemas to the single schema they are merged into
+	flattened map[*openapi3.Schema]*openapi3.Schema
+	// interfaces are all GraphQL interfaces, interfaceNames maps the extended schema names to their interface
+	interfaces     []GqlInterface
+	interfaceNames map[string]string
+
+	// scalars are custom scalars we use, on top of the named types that turn out to be scalars.
+	// scalarNames maps the requested scalar names to the declared ones, which differ in case of collisions
+	scalars     []string
+	scalarNames map[string]string
+
+	// componentNames maps the names of components.schemas to their GraphQL name, which differ in case of collisions
+	componentNames map[string]string
+	// componentSchemas maps the schemas of components.schemas that become named types to their component name
+	componentSchemas map[*openapi3.Schema]string
+	// errorTypes maps status code and body type of error responses to the synthetic type declared for them
+	errorTypes map[string]string
+	// symbols holds every name in use and resolves collisions
+	symbols *symbolTable
+	// order is the declaration order of the document, it is nil unless we are ordering by source
+	order *sourceOrder
+}
+
+func newConverter(doc *openapi3.T, opts Options) *converter {
+	return &converter{
+		doc:              doc,
+		opts:             opts,
+		types:            make([]GqlType, 0),
+		hoisted:          make(map[*openapi3.Schema]string),
+		inputs:           make([]*GqlInput, 0),
+		inputNames:       make(map[*openapi3.Schema]string),
+		enums:            make([]GqlEnum, 0),
+		enumNames:        make(map[*openapi3.Schema]string),
+		unions:           make([]GqlUnion, 0),
+		unionNames:       make(map[*openapi3.Schema]string),
+		flattened:        make(map[*openapi3.Schema]*openapi3.Schema),
+		interfaces:       make([]GqlInterface, 0),
+		interfaceNames:   make(map[string]string),
+		scalars:          make([]string, 0),
+		scalarNames:      make(map[string]string),
+		componentNames:   make(map[string]string),
+		componentSchemas: make(map[*openapi3.Schema]string),
+		errorTypes:       make(map[string]string),
+		symbols:          newSymbolTable(),
+	}
+}
+
+// componentOrder returns the names of components.schemas in the order they are converted in
+func (c *converter) componentOrder() []string {
+	if c.order == nil {
+		return util.SortedKeys(c.doc.Components.Schemas)
+	}
+	return inOrder(c.order.schemas, util.SortedKeys(c.doc.Components.Schemas))
+}
+
+// pathOrder returns the paths in the order they are converted in
+func (c *converter) pathOrder() []string {
+	if c.order == nil {
+		return util.SortedKeys(c.doc.Paths)
+	}
+	return inOrder(c.order.paths, util.SortedKeys(c.doc.Paths))
+}
+
+// pathOperations returns the operations of a path in the order they are converted in
+func (c *converter) pathOperations(url string) []pathOperation {
+	operations := pathOperations(c.doc.Paths[url])
+	if c.order == nil {
+		return operations
+	}
+	methods := c.order.methods[url]
+	sort.SliceStable(operations, func(i, j int) bool {
+		return methodIndex(methods, operations[i].kind) < methodIndex(methods, operations[j].kind)
+	})
+	return operations
+}
+
+func methodIndex(methods []oasOperationKind, kind oasOperationKind) int {
+	for idx, method := range methods {
+		if method == kind {
+			return idx
+		}
+	}
+	return len(methods)
+}
+
+// propertyNames returns the property names of a schema in the order they are converted in
+func (c *converter) propertyNames(schema *openapi3.Schema) []string {
+	if c.order == nil {
+		return util.SortedKeys(schema.Properties)
+	}
+	return inOrder(c.order.properties[schema], util.SortedKeys(schema.Properties))
+}
+
+// headerNames returns the header names of a response in the order they are converted in
+func (c *converter) headerNames(response *openapi3.Response) []string {
+	if c.order == nil {
+		return util.SortedKeys(response.Headers)
+	}
+	return inOrder(c.order.headers[response], util.SortedKeys(response.Headers))
+}
+
+// inOrder returns all names, the ones known to the declaration order first. Schemas constructed by kin-openapi
+// or ourselves have no declaration, they are appended in their given order
+func inOrder(declared []string, names []string) []string {
+	ordered := make([]string, 0, len(names))
+	for _, name := range declared {
+		if util.IsInSlice(name, names) && !util.IsInSlice(name, ordered) {
+			ordered = append(ordered, name)
+		}
+	}
+	for _, name := range names {
+		if !util.IsInSlice(name, ordered) {
+			ordered = append(ordered, name)
+		}
+	}
+	return ordered
+}
+
+func (c *converter) addType(gqlType GqlType) {
+	c.types = append(c.types, gqlType)
+}
+
+func (c *converter) addEnum(enum GqlEnum) {
+	c.enums = append(c.enums, enum)
+}
+
+func (c *converter) addUnion(union GqlUnion) {
+	c.unions = append(c.unions, union)
+}
+
+func (c *converter) addInterface(iface GqlInterface) {
+	c.interfaces = append(c.interfaces, iface)
+}
+
+// useScalar makes sure a custom scalar is declared and returns its name, which might be changed due to a collision
+func (c *converter) useScalar(name string) string {
+	if declared, ok := c.scalarNames[name]; ok {
+		return declared
+	}
+	declared := c.symbols.declareType(name, symbolScalar, "scalar "+name)
+	c.scalarNames[name] = declared
+	c.addScalar(declared)
+	return declared
+}
+
+// addScalar registers a scalar whose name is already declared, every scalar is registered once
+func (c *converter) addScalar(name string) {
+	if !util.IsInSlice(name, c.scalars) {
+		c.scalars = append(c.scalars, name)
+	}
+}
+
+func (c *converter) addInput(input *GqlInput) {
+	c.inputs = append(c.inputs, input)
+}
+
+// collectInputs returns all registered input types in the order they were generated
+func (c *converter) collectInputs() []GqlInput {
+	inputs := make([]GqlInput, 0, len(c.inputs))
+	for _, input := range c.inputs {
+		inputs = append(inputs, *input)
+	}
+	return inputs
+}
@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/FrauElster/gopenApiToGraphQL/util"
+	"github.com/getkin/kin-openapi/openapi3"
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+)
+
+// gqlDeprecated is the built-in directive marking fields, arguments and enum values as deprecated
+const gqlDeprecated = "deprecated"
+
+// deprecatedDirective returns the @deprecated directive, GraphQL uses a default reason if none is given
+func deprecatedDirective(reason string) GqlDirective {
+	directive := GqlDirective{Name: gqlDeprecated}
+	if reason = strings.TrimSpace(reason); reason != "" {
+		directive.Arguments = []GqlDirectiveArgument{{Name: "reason", Value: reason}}
+	}
+	return directive
+}
+
+// deprecation returns the directives for an OpenAPI element that is possibly deprecated.
+// The reason is taken from the "x-deprecated-reason" extension, falling back to the description
+func deprecation(deprecated bool, props openapi3.ExtensionProps, description string) []GqlDirective {
+	if !deprecated {
+		return nil
+	}
+	reason := description
+	var extensionReason string
+	if decodeExtension(props, "x-deprecated-reason", &extensionReason) {
+		reason = extensionReason
+	}
+	return []GqlDirective{deprecatedDirective(reason)}
+}
+
+// String renders the directive as it is used in the schema, e.g. @deprecated(reason: "use v2")
+func (directive GqlDirective) String() string {
+	if len(directive.Arguments) == 0 {
+		return "@" + directive.Name
+	}
+	arguments := make([]string, 0, len(directive.Arguments))
+	for _, argument := range directive.Arguments {
+		arguments = append(arguments, fmt.Sprintf("%s: %s", argument.Name, gqlValue(argument.Value)))
+	}
+	return fmt.Sprintf("@%s(%s)", directive.Name, strings.Join(arguments, ", "))
+}
+
+// gqlValue renders a Go value as GraphQL literal
+func gqlValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return quoteGqlString(v)
+	case []string:
+		values := make([]string, 0, len(v))
+		for _, it := range v {
+			values = append(values, quoteGqlString(it))
+		}
+		return fmt.Sprintf("[%s]", strings.Join(values, ", "))
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// directiveDefinitions returns the definitions of all custom directives used anywhere in the spec,
+// together with the ones already declared on it. Built-in directives have no definition and are skipped
+func (spec *GqlSpec) directiveDefinitions() []GqlDirectiveDefinition {
+	definitions := make(map[string]GqlDirectiveDefinition)
+	for _, definition := range spec.Directives {
+		definitions[definition.Name] = definition
+	}
+	collect := func(directives []GqlDirective) {
+		for _, directive := range directives {
+			if directive.Definition != nil {
+				definitions[directive.Name] = *directive.Definition
+			}
+		}
+	}
+	collectAttributes := func(attributes []GqlAttribute) {
+		for _, attribute := range attributes {
+			collect(attribute.Directives)
+		}
+	}
+
+	for _, gqlType := range spec.Types {
+		collectAttributes(gqlType.Attributes)
+	}
+	for _, iface := range spec.Interfaces {
+		collectAttributes(iface.Attributes)
+	}
+	for _, input := range spec.Inputs {
+		collectAttributes(input.Attributes)
+	}
+	for _, operations := range [][]GqlOperation{spec.Queries, spec.Mutations} {
+		for _, operation := range operations {
+			collect(operation.Directives)
+			collectAttributes(operation.Parameters)
+		}
+	}
+
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]GqlDirectiveDefinition, 0, len(names))
+	for _, name := range names {
+		result = append(result, definitions[name])
+	}
+	return result
+}
+
+// argumentDeprecation drops @deprecated from required arguments and input fields, which GraphQL does not allow,
+// since a client can't stop sending them
+func argumentDeprecation(name string, isRequired bool, directives []GqlDirective) []GqlDirective {
+	if !isRequired {
+		return directives
+	}
+	return util.FilterSlice(directives, func(directive GqlDirective) bool {
+		if directive.Name == gqlDeprecated {
+			log.Warnf("%s - is required and can't be deprecated in GraphQL, dropping @%s", name, gqlDeprecated)
+			return false
+		}
+		return true
+	})
+}
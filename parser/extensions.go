@@ -0,0 +1,25 @@
+package parser
+
+import (
+	"encoding/json"
+	"github.com/getkin/kin-openapi/openapi3"
+	log "github.com/sirupsen/logrus"
+)
+
+// decodeExtension decodes the OpenAPI extension (the "x-" properties) with the given name into target.
+// It returns false if the extension is not present or has an unexpected format
+func decodeExtension(props openapi3.ExtensionProps, name string, target interface{}) bool {
+	raw, ok := props.Extensions[name]
+	if !ok {
+		return false
+	}
+	rawJson, ok := raw.(json.RawMessage)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(rawJson, target); err != nil {
+		log.Warnf("ignoring extension %s: %s", name, err)
+		return false
+	}
+	return true
+}
@@ -4,21 +4,49 @@ import (
 	"bytes"
 	_ "embed"
 	"fmt"
-	"html/template"
+	"github.com/FrauElster/gopenApiToGraphQL/util"
+	"github.com/getkin/kin-openapi/openapi3"
 	"log"
-	"net/http"
+	"strings"
+	"text/template"
 	"time"
 )
 
 type oasOperationKind string
 
 const (
-	oasGet    oasOperationKind = "GET"
-	oasPut    oasOperationKind = "PUT"
-	oasPost   oasOperationKind = "POST"
-	oasDelete oasOperationKind = "DELETE"
+	oasGet     oasOperationKind = "GET"
+	oasPut     oasOperationKind = "PUT"
+	oasPost    oasOperationKind = "POST"
+	oasDelete  oasOperationKind = "DELETE"
+	oasPatch   oasOperationKind = "PATCH"
+	oasHead    oasOperationKind = "HEAD"
+	oasOptions oasOperationKind = "OPTIONS"
+	oasTrace   oasOperationKind = "TRACE"
+	oasConnect oasOperationKind = "CONNECT"
 )
 
+type pathOperation struct {
+	kind      oasOperationKind
+	operation *openapi3.Operation
+}
+
+// pathOperations returns all operations of a path, always in the same order
+func pathOperations(path *openapi3.PathItem) []pathOperation {
+	all := []pathOperation{
+		{oasGet, path.Get},
+		{oasHead, path.Head},
+		{oasOptions, path.Options},
+		{oasTrace, path.Trace},
+		{oasConnect, path.Connect},
+		{oasDelete, path.Delete},
+		{oasPost, path.Post},
+		{oasPut, path.Put},
+		{oasPatch, path.Patch},
+	}
+	return util.FilterSlice(all, func(it pathOperation) bool { return it.operation != nil })
+}
+
 type oasBaseType string
 
 const (
@@ -28,8 +56,6 @@ const (
 	oasBool   oasBaseType = "boolean"
 )
 
-const gqlDeprecated = "@deprecated"
-
 type gqlBaseType string
 
 const (
@@ -37,6 +63,7 @@ const (
 	gqlFloat   gqlBaseType = "Float"
 	gqlInt     gqlBaseType = "Int"
 	gqlBoolean gqlBaseType = "Boolean"
+	gqlID      gqlBaseType = "ID"
 )
 
 func baseTypeConversion(oas oasBaseType) (gqlBaseType, error) {
@@ -55,51 +82,170 @@ func baseTypeConversion(oas oasBaseType) (gqlBaseType, error) {
 	return gqlString, fmt.Errorf("could not convert \"%s\" to a gqlBaseTye: not a valid oasBaseType", oas)
 }
 
+// isBuiltInScalar reports whether a scalar is part of GraphQL itself and must not be declared
+func isBuiltInScalar(name string) bool {
+	switch gqlBaseType(name) {
+	case gqlString, gqlFloat, gqlInt, gqlBoolean, gqlID:
+		return true
+	}
+	return false
+}
+
 const (
 	GqlTypeTemplateName   = "gqlType"
 	GqlScalarTemplateName = "gqlScalar"
 )
 
 type GqlScalar struct {
-	Name string
+	Name        string
+	Description string
 }
 
 type GqlOperation struct {
-	Origin     string
-	Name       string
-	Parameters []GqlAttribute
-	ReturnType string
-	Hints      []string
+	Origin      string
+	Name        string
+	Description string
+	Parameters  []GqlAttribute
+	ReturnType  string
+	Directives  []GqlDirective
+	// RequestMediaType is the media type the request body is sent as, e.g. application/json.
+	// It is empty if the operation has no request body
+	RequestMediaType string
+	// ResponseMediaType is the media type of the response the return type was generated from, as it would be accepted.
+	// Result unions accept the media types of all their responses, e.g. 'application/json, application/problem+json'
+	ResponseMediaType string
 }
 
 type GqlAttribute struct {
-	Name       string
-	Type       string
-	IsRequired bool
-	Hints      []string
+	// Name is the sanitized GraphQL name
+	Name string
+	// WireName is the name of the property or parameter as it is sent over the wire, e.g. "user-name" for userName.
+	// It is empty for attributes that have no OpenAPI counterpart
+	WireName    string
+	Description string
+	Type        string
+	IsRequired  bool
+	Directives  []GqlDirective
 }
 
 type GqlType struct {
-	Name       string
-	Type       string
+	Name        string
+	Description string
+	Type        string
+	Attributes  []GqlAttribute
+	// Interfaces are the names of all GqlInterface s the type implements
+	Interfaces []string
+}
+
+// GqlInterface is a GraphQL interface, generated for schemas other schemas extend via allOf
+type GqlInterface struct {
+	Name        string
+	Description string
+	// Base is the name of the type the interface was generated from
+	Base       string
 	Attributes []GqlAttribute
+	// Discriminator tells which implementation an object is, if the OpenAPI schema specified one
+	Discriminator *GqlDiscriminator
+}
+
+// GqlEnum is a GraphQL enum, its values keep track of the original OpenAPI value they were generated from
+type GqlEnum struct {
+	Name        string
+	Description string
+	Values      []GqlEnumValue
+}
+
+type GqlEnumValue struct {
+	// Name is the sanitized GraphQL enum value
+	Name        string
+	Description string
+	// Value is the value as it is sent over the wire
+	Value interface{}
+}
+
+// GqlUnion is a GraphQL union, generated from oneOf and anyOf
+type GqlUnion struct {
+	Name        string
+	Description string
+	Members     []string
+	// Discriminator tells which member an object is, if the OpenAPI schema specified one
+	Discriminator *GqlDiscriminator
+}
+
+type GqlDiscriminator struct {
+	// PropertyName is the property holding the discriminating value
+	PropertyName string
+	// Mapping maps the discriminating values to the name of the union member
+	Mapping map[string]string
+}
+
+// GqlDirective is the usage of a directive, e.g. @deprecated(reason: "use v2")
+type GqlDirective struct {
+	Name      string
+	Arguments []GqlDirectiveArgument
+	// Definition declares custom directives, it is nil for the ones built into GraphQL
+	Definition *GqlDirectiveDefinition
+}
+
+type GqlDirectiveArgument struct {
+	Name string
+	// Value is rendered as GraphQL literal, e.g. strings are quoted
+	Value interface{}
+}
+
+// GqlDirectiveDefinition declares a custom directive, e.g. directive @example(value: String) on FIELD_DEFINITION
+type GqlDirectiveDefinition struct {
+	Name        string
+	Description string
+	Arguments   []GqlAttribute
+	Locations   []string
+}
+
+// GqlDiagnostic reports something the user should know about the conversion, e.g. a type that had to be renamed
+type GqlDiagnostic struct {
+	// Origin is where in the OpenAPI spec the problem was found
+	Origin  string
+	Message string
+}
+
+func (diagnostic GqlDiagnostic) String() string {
+	return fmt.Sprintf("%s: %s", diagnostic.Origin, diagnostic.Message)
+}
+
+// GqlInput is a GraphQL input type, generated from request bodies since GraphQL arguments can't use regular types
+type GqlInput struct {
+	Name        string
+	Description string
+	Attributes  []GqlAttribute
 }
 
 type GqlSpec struct {
+	// GenerationTime is rendered as comment, unless it is zero
 	GenerationTime time.Time
 	Types          []GqlType
-	Scalars        []GqlScalar
-	Mutations      []GqlOperation
-	Queries        []GqlOperation
+	Inputs         []GqlInput
+	Enums          []GqlEnum
+	Unions         []GqlUnion
+	Interfaces     []GqlInterface
+	Directives     []GqlDirectiveDefinition
+	// Diagnostics are not rendered, they inform about everything noteworthy that happened during the conversion
+	Diagnostics []GqlDiagnostic
+	Scalars     []GqlScalar
+	Mutations   []GqlOperation
+	Queries     []GqlOperation
 }
 
 //go:embed gqlSchema.tmpl
 var gqlSpecTemplate string
 
 func (spec *GqlSpec) String() string {
-	templ := template.Must(template.New("gqlSchema").Parse(gqlSpecTemplate))
+	templ := template.Must(template.New("gqlSchema").Funcs(template.FuncMap{
+		"description":     gqlDescription,
+		"hasDescriptions": hasDescriptions,
+		"join":            strings.Join,
+	}).Parse(gqlSpecTemplate))
 
-	spec.GenerationTime = time.Now()
+	spec.Directives = spec.directiveDefinitions()
 	buf := new(bytes.Buffer)
 	err := templ.Execute(buf, spec)
 	if err != nil {
@@ -109,67 +255,59 @@ func (spec *GqlSpec) String() string {
 	return buf.String()
 }
 
-var httpCodes = []int{
-	http.StatusContinue,
-	http.StatusSwitchingProtocols,
-	http.StatusProcessing,
-	http.StatusEarlyHints,
-	http.StatusOK,
-	http.StatusCreated,
-	http.StatusAccepted,
-	http.StatusNonAuthoritativeInfo,
-	http.StatusNoContent,
-	http.StatusResetContent,
-	http.StatusPartialContent,
-	http.StatusMultiStatus,
-	http.StatusAlreadyReported,
-	http.StatusIMUsed,
-	http.StatusMultipleChoices,
-	http.StatusMovedPermanently,
-	http.StatusFound,
-	http.StatusSeeOther,
-	http.StatusNotModified,
-	http.StatusUseProxy,
-	http.StatusTemporaryRedirect,
-	http.StatusPermanentRedirect,
-	http.StatusBadRequest,
-	http.StatusUnauthorized,
-	http.StatusPaymentRequired,
-	http.StatusForbidden,
-	http.StatusNotFound,
-	http.StatusMethodNotAllowed,
-	http.StatusNotAcceptable,
-	http.StatusProxyAuthRequired,
-	http.StatusRequestTimeout,
-	http.StatusConflict,
-	http.StatusGone,
-	http.StatusLengthRequired,
-	http.StatusPreconditionFailed,
-	http.StatusRequestEntityTooLarge,
-	http.StatusRequestURITooLong,
-	http.StatusUnsupportedMediaType,
-	http.StatusRequestedRangeNotSatisfiable,
-	http.StatusExpectationFailed,
-	http.StatusTeapot,
-	http.StatusMisdirectedRequest,
-	http.StatusUnprocessableEntity,
-	http.StatusLocked,
-	http.StatusFailedDependency,
-	http.StatusTooEarly,
-	http.StatusUpgradeRequired,
-	http.StatusPreconditionRequired,
-	http.StatusTooManyRequests,
-	http.StatusRequestHeaderFieldsTooLarge,
-	http.StatusUnavailableForLegalReasons,
-	http.StatusInternalServerError,
-	http.StatusNotImplemented,
-	http.StatusBadGateway,
-	http.StatusServiceUnavailable,
-	http.StatusGatewayTimeout,
-	http.StatusHTTPVersionNotSupported,
-	http.StatusVariantAlsoNegotiates,
-	http.StatusInsufficientStorage,
-	http.StatusLoopDetected,
-	http.StatusNotExtended,
-	http.StatusNetworkAuthenticationRequired,
+// gqlDescription renders a description as GraphQL string on its own line, indented by indent.
+// Multi line descriptions become block strings, empty descriptions are not rendered at all
+func gqlDescription(description string, indent string) string {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return ""
+	}
+
+	if !strings.ContainsAny(description, "\n\r") {
+		return indent + quoteGqlString(description) + "\n"
+	}
+
+	// the only thing that has to be escaped in a block string is the closing """
+	description = strings.ReplaceAll(description, `"""`, `\"""`)
+	lines := strings.Split(strings.ReplaceAll(description, "\r\n", "\n"), "\n")
+	block := indent + `"""` + "\n"
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			block += "\n"
+			continue
+		}
+		block += indent + strings.TrimRight(line, " \t\r") + "\n"
+	}
+	return block + indent + `"""` + "\n"
+}
+
+// quoteGqlString quotes a single line as GraphQL string
+func quoteGqlString(value string) string {
+	builder := strings.Builder{}
+	builder.WriteRune('"')
+	for _, r := range value {
+		switch {
+		case r == '"' || r == '\\':
+			builder.WriteRune('\\')
+			builder.WriteRune(r)
+		case r == '\t':
+			builder.WriteString(`\t`)
+		case r < 0x20:
+			builder.WriteString(fmt.Sprintf(`\u%04x`, r))
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	builder.WriteRune('"')
+	return builder.String()
+}
+
+// hasDescriptions reports whether any of the attributes has a description, in which case we render them on separate lines
+func hasDescriptions(attributes []GqlAttribute) bool {
+	for _, attribute := range attributes {
+		if strings.TrimSpace(attribute.Description) != "" {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"github.com/FrauElster/gopenApiToGraphQL/util"
+	"github.com/getkin/kin-openapi/openapi3"
+	"mime"
+	"strings"
+)
+
+// DefaultMediaTypes returns the media type preference used by DefaultOptions, which are all the flavours of JSON,
+// e.g. application/problem+json or application/vnd.api+json;version=2
+func DefaultMediaTypes() []string {
+	return []string{
+		"application/json",
+		"application/*+json",
+		"*/*+json",
+		"text/json",
+		"application/x-json",
+		"text/x-json",
+	}
+}
+
+// preferredMediaType returns the content we convert, which is the first one matching Options.MediaTypes and having
+// a schema. It is returned with the media type to send, which is the documented one, or the preferred one if the
+// documented one is a wildcard, e.g. '*/*' is negotiated to application/json. Nil is returned if none matches
+func (c *converter) preferredMediaType(content openapi3.Content) (string, *openapi3.MediaType) {
+	// sorted, so we take the same one every time if several match, e.g. application/hal+json and application/problem+json
+	documented := util.SortedKeys(content)
+	for _, preferred := range c.opts.MediaTypes {
+		for _, mediaType := range documented {
+			if !mediaTypeMatches(preferred, mediaType) {
+				continue
+			}
+			if content[mediaType] == nil || content[mediaType].Schema == nil {
+				// the schema really should not be nil, but my real-world test set had it sometimes
+				continue
+			}
+			if isMediaRange(mediaType) && !isMediaRange(preferred) {
+				return preferred, content[mediaType]
+			}
+			return mediaType, content[mediaType]
+		}
+	}
+	return "", nil
+}
+
+// mediaTypeMatches reports whether two media types are compatible. Both may be ranges, e.g. '*/*' or 'application/*',
+// and the subtype may be a structured syntax suffix, e.g. '*+json' matches 'problem+json'.
+// Parameters, e.g. ';version=2', are ignored
+func mediaTypeMatches(a string, b string) bool {
+	aType, aSubtype := splitMediaType(a)
+	bType, bSubtype := splitMediaType(b)
+	return mediaRangeMatches(aType, bType) && mediaRangeMatches(aSubtype, bSubtype)
+}
+
+func mediaRangeMatches(a string, b string) bool {
+	switch {
+	case a == "*" || b == "*" || a == b:
+		return true
+	case strings.HasPrefix(a, "*+"):
+		return strings.HasSuffix(b, a[1:])
+	case strings.HasPrefix(b, "*+"):
+		return strings.HasSuffix(a, b[1:])
+	}
+	return false
+}
+
+// splitMediaType splits a media type into its lower case type and subtype, e.g. 'Application/HAL+JSON; charset=utf-8'
+// -> (application, hal+json)
+func splitMediaType(mediaType string) (string, string) {
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		mediaType = parsed
+	}
+	mediaType, _, _ = strings.Cut(strings.ToLower(mediaType), ";")
+	mainType, subtype, found := strings.Cut(strings.TrimSpace(mediaType), "/")
+	if !found {
+		// a lonely '*' is sometimes used for '*/*'
+		return mainType, "*"
+	}
+	return mainType, subtype
+}
+
+// isMediaRange reports whether a media type is a wildcard, which can't be sent as is
+func isMediaRange(mediaType string) bool {
+	return strings.Contains(mediaType, "*")
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"github.com/FrauElster/gopenApiToGraphQL/util"
+	"github.com/getkin/kin-openapi/openapi3"
+	"testing"
+)
+
+func TestMediaTypeMatches(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"application/json", "application/json", true},
+		{"application/json", "Application/JSON; charset=utf-8", true},
+		{"application/*+json", "application/problem+json", true},
+		{"application/*+json", "application/vnd.api+json;version=2", true},
+		{"*/*+json", "text/hal+json", true},
+		{"application/json", "*/*", true},
+		{"application/json", "application/*", true},
+		{"application/json", "*", true},
+		{"application/*+json", "application/json", false},
+		{"application/json", "application/xml", false},
+		{"application/json", "text/json", false},
+		{"application/*+json", "application/problem+xml", false},
+	}
+	for _, tt := range tests {
+		if got := mediaTypeMatches(tt.a, tt.b); got != tt.want {
+			t.Errorf("mediaTypeMatches(%q, %q) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestPreferredMediaType(t *testing.T) {
+	withSchema := func(mediaTypes ...string) openapi3.Content {
+		content := openapi3.Content{}
+		for _, mediaType := range mediaTypes {
+			content[mediaType] = openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema())
+		}
+		return content
+	}
+	tests := []struct {
+		content openapi3.Content
+		want    string
+	}{
+		{withSchema("application/xml", "application/json"), "application/json"},
+		{withSchema("application/xml", "application/problem+json"), "application/problem+json"},
+		{withSchema("application/hal+json", "application/problem+json"), "application/hal+json"},
+		{withSchema("application/vnd.api+json;version=2"), "application/vnd.api+json;version=2"},
+		// wildcards are negotiated to the preferred media type
+		{withSchema("*/*"), "application/json"},
+		{withSchema("application/xml"), ""},
+		{openapi3.Content{"application/json": openapi3.NewMediaType()}, ""},
+	}
+	c := newConverter(&openapi3.T{}, DefaultOptions())
+	for _, tt := range tests {
+		if got, _ := c.preferredMediaType(tt.content); got != tt.want {
+			t.Errorf("preferredMediaType(%v) = %q, want %q", util.SortedKeys(tt.content), got, tt.want)
+		}
+	}
+}
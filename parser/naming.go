@@ -0,0 +1,210 @@
+package parser
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// gqlNamePattern is what GraphQL allows as name, see https://spec.graphql.org/October2021/#Name
+var gqlNamePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// invalidNameChars are all characters that must not be part of a GraphQL name
+var invalidNameChars = regexp.MustCompile(`[^_0-9A-Za-z]+`)
+
+// isValidGqlName reports whether name can be used as is. Names starting with "__" are valid syntax,
+// but reserved for GraphQL introspection
+func isValidGqlName(name string) bool {
+	return gqlNamePattern.MatchString(name) && !strings.HasPrefix(name, "__")
+}
+
+// toTypeName turns any schema name into a valid GraphQL type name, e.g. "user-profile" -> UserProfile.
+// Valid names are kept as they are, so "HTTPConfig" stays HTTPConfig
+func toTypeName(name string) string {
+	return sanitizeName(name, true)
+}
+
+// toFieldName turns any property name into a valid GraphQL field name, e.g. "@type" -> type, "2fa" -> _2fa
+func toFieldName(name string) string {
+	return sanitizeName(name, false)
+}
+
+func sanitizeName(name string, isType bool) string {
+	if isValidGqlName(name) {
+		return name
+	}
+
+	// every run of invalid characters separates words, which are joined in camelCase, e.g. "Foo.Bar" -> FooBar
+	words := make([]string, 0)
+	for _, word := range invalidNameChars.Split(name, -1) {
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	for idx := range words {
+		if idx > 0 || isType {
+			words[idx] = upperFirst(words[idx])
+		}
+	}
+	sanitized := strings.Join(words, "")
+
+	// "__" is reserved for introspection, so we keep a single underscore
+	if strings.HasPrefix(sanitized, "__") {
+		sanitized = "_" + strings.TrimLeft(sanitized, "_")
+	}
+	// GraphQL names must not start with a digit, and must not be empty, e.g. for a property named "@"
+	if sanitized == "" || unicode.IsDigit(rune(sanitized[0])) {
+		sanitized = "_" + sanitized
+	}
+	return sanitized
+}
+
+// decodeRefToken decodes a single JSON pointer token, e.g. "Foo~1Bar" -> Foo/Bar, and its URI encoding,
+// e.g. "Foo%20Bar" -> "Foo Bar", as it appears in references
+func decodeRefToken(token string) string {
+	if unescaped, err := url.PathUnescape(token); err == nil {
+		token = unescaped
+	}
+	// the order matters, "~01" is "~1" and not "/"
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// encodeRefToken encodes a name as JSON pointer token, e.g. "Foo/Bar" -> Foo~1Bar
+func encodeRefToken(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+// splitWords splits an identifier into its words. Words are separated by every non character or integer and by
+// case changes, acronyms are kept together, e.g. "get-HTTPStatus_code" -> [get HTTP Status code]
+func splitWords(name string) []string {
+	words := make([]string, 0)
+	current := make([]rune, 0)
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = make([]rune, 0)
+		}
+	}
+
+	runes := []rune(name)
+	for idx, r := range runes {
+		if !isAsciiAlphaNumeric(r) {
+			flush()
+			continue
+		}
+		if len(current) > 0 && unicode.IsUpper(r) {
+			previous := current[len(current)-1]
+			nextIsLower := idx+1 < len(runes) && unicode.IsLower(runes[idx+1])
+			// "userId" -> user Id, "HTTPStatus" -> HTTP Status
+			if !unicode.IsUpper(previous) || nextIsLower {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+
+	return words
+}
+
+func isAsciiAlphaNumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// toCamelCase converts any identifier to camelCase, e.g. "get-user_by-ID" -> getUserById.
+// Acronyms are treated as ordinary words, so "HTTPStatus" becomes httpStatus
+func toCamelCase(name string) string {
+	words := splitWords(name)
+	for idx := range words {
+		word := strings.ToLower(words[idx])
+		// upper each starting char for every word except the first one
+		if idx > 0 {
+			word = upperFirst(word)
+		}
+		words[idx] = word
+	}
+
+	camel := strings.Join(words, "")
+	// GraphQL names must not start with a digit
+	if camel != "" && unicode.IsDigit(rune(camel[0])) {
+		camel = "_" + camel
+	}
+	return camel
+}
+
+// toCamelName turns an identifier from the spec into a camelCase GraphQL name, e.g. "get-user" -> getUser.
+// Valid names are kept as they are, so "getHTTPStatus" stays getHTTPStatus and existing resolvers keep their name
+func toCamelName(name string) string {
+	if isValidGqlName(name) {
+		return name
+	}
+	return toFieldName(toCamelCase(name))
+}
+
+// upperFirst upper cases the first character, e.g. to turn a camelCase operation name into a PascalCase type name
+func upperFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	// first char to upper case https://stackoverflow.com/a/70259366
+	r := []rune(name)
+	return string(append([]rune{unicode.ToUpper(r[0])}, r[1:]...))
+}
+
+// operationNameFromPath derives an operation name from method and path, for operations without operationId.
+// Collections addressed by a path parameter are singularized and the parameters are appended,
+// e.g. GET /users/{id} -> getUserById, DELETE /users/{userId}/posts/{postId} -> deleteUserPostByUserIdAndPostId
+func operationNameFromPath(kind oasOperationKind, url string) string {
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(url, "/") {
+		// leading, trailing and double slashes result in empty segments
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	words := []string{strings.ToLower(string(kind))}
+	params := make([]string, 0)
+	for idx, segment := range segments {
+		if isPathParameter(segment) {
+			params = append(params, upperFirst(toCamelCase(strings.Trim(segment, "{}"))))
+			continue
+		}
+		segmentWords := splitWords(segment)
+		// "users/{id}" addresses a single user
+		if len(segmentWords) > 0 && idx+1 < len(segments) && isPathParameter(segments[idx+1]) {
+			segmentWords[len(segmentWords)-1] = singularize(segmentWords[len(segmentWords)-1])
+		}
+		words = append(words, segmentWords...)
+	}
+	if len(words) == 1 {
+		words = append(words, "root")
+	}
+
+	name := toCamelCase(strings.Join(words, " "))
+	if len(params) > 0 {
+		name += "By" + strings.Join(params, "And")
+	}
+	return name
+}
+
+func isPathParameter(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// singularize is a very naive english singularization, but good enough for the usual REST collection names
+func singularize(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "us"), strings.HasSuffix(lower, "is"):
+		return word
+	case strings.HasSuffix(lower, "s") && len(word) > 1:
+		return word[:len(word)-1]
+	}
+	return word
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"getUser", []string{"get", "User"}},
+		{"get-HTTPStatus_code", []string{"get", "HTTP", "Status", "code"}},
+		{"userID", []string{"user", "ID"}},
+		{"v2Users", []string{"v2", "Users"}},
+		{"--", []string{}},
+	}
+	for _, tt := range tests {
+		if got := splitWords(tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitWords(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestToCamelCase(t *testing.T) {
+	tests := map[string]string{
+		"get-user_by-ID": "getUserById",
+		"HTTPStatus":     "httpStatus",
+		"ListPets":       "listPets",
+		"2fa-code":       "_2faCode",
+	}
+	for name, want := range tests {
+		if got := toCamelCase(name); got != want {
+			t.Errorf("toCamelCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestToCamelName(t *testing.T) {
+	tests := map[string]string{
+		// valid names are kept, so existing resolvers keep their name
+		"getHTTPStatus": "getHTTPStatus",
+		"ListPets":      "ListPets",
+		"get_q":         "get_q",
+		"get-user":      "getUser",
+		"X-Total-Count": "xTotalCount",
+		"__typename":    "typename",
+	}
+	for name, want := range tests {
+		if got := toCamelName(name); got != want {
+			t.Errorf("toCamelName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name   string
+		isType bool
+		want   string
+	}{
+		{"HTTPConfig", true, "HTTPConfig"},
+		{"user-profile", true, "UserProfile"},
+		{"Foo.Bar", true, "FooBar"},
+		{"@type", false, "type"},
+		{"user-name", false, "userName"},
+		{"2fa", false, "_2fa"},
+		{"@", false, "_"},
+		{"__schema", true, "_schema"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeName(tt.name, tt.isType); got != tt.want {
+			t.Errorf("sanitizeName(%q, %t) = %q, want %q", tt.name, tt.isType, got, tt.want)
+		}
+	}
+}
+
+func TestSingularize(t *testing.T) {
+	tests := map[string]string{
+		"users":      "user",
+		"categories": "category",
+		"addresses":  "address",
+		"boxes":      "box",
+		"branches":   "branch",
+		"status":     "status",
+		"analysis":   "analysis",
+		"class":      "class",
+		"s":          "s",
+	}
+	for word, want := range tests {
+		if got := singularize(word); got != want {
+			t.Errorf("singularize(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestOperationNameFromPath(t *testing.T) {
+	tests := []struct {
+		kind oasOperationKind
+		url  string
+		want string
+	}{
+		{oasGet, "/users", "getUsers"},
+		{oasGet, "/users/{id}", "getUserById"},
+		{oasDelete, "/users/{userId}/posts/{postId}", "deleteUserPostByUserIdAndPostId"},
+		{oasPost, "/user-profiles/", "postUserProfiles"},
+		{oasGet, "/", "getRoot"},
+	}
+	for _, tt := range tests {
+		if got := operationNameFromPath(tt.kind, tt.url); got != tt.want {
+			t.Errorf("operationNameFromPath(%s, %q) = %q, want %q", tt.kind, tt.url, got, tt.want)
+		}
+	}
+}
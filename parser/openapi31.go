@@ -0,0 +1,308 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/FrauElster/gopenApiToGraphQL/util"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// oas30Version is the version OpenAPI 3.1 documents are rewritten to, which is the one kin-openapi understands
+const oas30Version = "3.0.3"
+
+// valueKeys hold plain values instead of schemas, e.g. an example object with a "type" property,
+// so we must not rewrite anything in there
+var valueKeys = []string{"example", "examples", "default", "enum", "const"}
+
+// normalizeOas31 rewrites an OpenAPI 3.1 document to OpenAPI 3.0, documents of other versions are returned unchanged.
+// The 3.1 schemas are JSON Schema 2020-12, whose constructs we map to their 3.0 equivalent, e.g.
+// 'type: [string, "null"]' -> 'type: string, nullable: true' and 'const: dog' -> 'enum: [dog]'.
+// The rewrite works on the YAML tree, so the declaration order is kept
+func normalizeOas31(data []byte) ([]byte, error) {
+	root := yaml.Node{}
+	err := yaml.Unmarshal(data, &root)
+	if err != nil || len(root.Content) == 0 {
+		// not our business, the loader will report it
+		return data, nil
+	}
+	document := root.Content[0]
+	version := mappingValue(document, "openapi")
+	if version == nil || !strings.HasPrefix(version.Value, "3.1") {
+		return data, nil
+	}
+	version.Value = oas30Version
+
+	// webhooks are called by the API, not by us, so there is nothing to convert
+	if webhooks := mappingKeys(mappingValue(document, "webhooks")); len(webhooks) > 0 {
+		log.Infof("skipping webhooks %s, they are called by the API and have no GraphQL counterpart", strings.Join(webhooks, ", "))
+	}
+	removeKey(document, "webhooks")
+	removeKey(document, "jsonSchemaDialect")
+	// paths are optional since 3.1
+	if mappingValue(document, "paths") == nil {
+		setKey(document, "paths", &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+	}
+
+	hoistDefs(document)
+	for _, key := range mappingKeys(document) {
+		if key != "components" {
+			normalizeNode(mappingValue(document, key), false)
+			continue
+		}
+		// components map names to schemas, parameters and so on, which must not be taken for keywords
+		components := mappingValue(document, key)
+		for _, kind := range mappingKeys(components) {
+			normalizeNode(mappingValue(components, kind), true)
+		}
+	}
+
+	normalized, err := yaml.Marshal(&root)
+	if err != nil {
+		return nil, fmt.Errorf("could not normalize OpenAPI 3.1: %w", err)
+	}
+	return normalized, nil
+}
+
+// normalizeNode normalizes every schema in node. isNameMap tells whether the node maps names to schemas,
+// e.g. properties, in which case its keys are names and not keywords, e.g. a property called "const"
+func normalizeNode(node *yaml.Node, isNameMap bool) {
+	node = resolveAlias(node)
+	if node == nil {
+		return
+	}
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			normalizeNode(item, false)
+		}
+	case yaml.MappingNode:
+		if !isNameMap {
+			normalizeSchema(node)
+		}
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			key := node.Content[idx].Value
+			if !isNameMap && (util.IsInSlice(key, valueKeys) || strings.HasPrefix(key, "x-")) {
+				continue
+			}
+			normalizeNode(node.Content[idx+1], !isNameMap && (key == "properties" || key == "patternProperties"))
+		}
+	}
+}
+
+// normalizeSchema rewrites the JSON Schema 2020-12 keywords of a single schema. Nodes that are no schema are not
+// affected, since they don't use these keywords
+func normalizeSchema(node *yaml.Node) {
+	// 'type: [string, "null"]' is the 3.1 way of nullable, multiple types are alternatives
+	if types := mappingValue(node, "type"); types != nil {
+		switch {
+		case types.Kind == yaml.SequenceNode:
+			nonNull := make([]*yaml.Node, 0)
+			for _, item := range types.Content {
+				if item.Value == "null" {
+					setKey(node, "nullable", scalarNode("true", "!!bool"))
+				} else {
+					nonNull = append(nonNull, item)
+				}
+			}
+			removeKey(node, "type")
+			if len(nonNull) == 1 {
+				setKey(node, "type", nonNull[0])
+			} else if len(nonNull) > 1 && mappingValue(node, "oneOf") == nil {
+				alternatives := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+				for _, item := range nonNull {
+					alternative := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+					setKey(alternative, "type", item)
+					alternatives.Content = append(alternatives.Content, alternative)
+				}
+				setKey(node, "oneOf", alternatives)
+			}
+		case types.Value == "null":
+			removeKey(node, "type")
+			setKey(node, "nullable", scalarNode("true", "!!bool"))
+		}
+	}
+
+	// 'oneOf: [$ref: Pet, type: "null"]' is nullable as well
+	for _, key := range []string{"oneOf", "anyOf"} {
+		alternatives := mappingValue(node, key)
+		if alternatives == nil || alternatives.Kind != yaml.SequenceNode {
+			continue
+		}
+		nonNull := make([]*yaml.Node, 0)
+		for _, alternative := range alternatives.Content {
+			if isNullSchema(alternative) {
+				setKey(node, "nullable", scalarNode("true", "!!bool"))
+			} else {
+				nonNull = append(nonNull, alternative)
+			}
+		}
+		alternatives.Content = nonNull
+		// a single alternative is no alternative, but the same as extending it
+		if len(nonNull) == 1 && mappingValue(node, "allOf") == nil {
+			removeKey(node, key)
+			setKey(node, "allOf", alternatives)
+		}
+	}
+
+	// a constant is an enum with a single value
+	if constant := mappingValue(node, "const"); constant != nil {
+		removeKey(node, "const")
+		if mappingValue(node, "enum") == nil {
+			setKey(node, "enum", &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{constant}})
+		}
+	}
+
+	// schemas have a list of examples now, instead of a single one
+	if examples := mappingValue(node, "examples"); examples != nil && examples.Kind == yaml.SequenceNode {
+		removeKey(node, "examples")
+		if len(examples.Content) > 0 && mappingValue(node, "example") == nil {
+			setKey(node, "example", examples.Content[0])
+		}
+	}
+
+	// exclusive bounds are numbers now, instead of a flag for minimum and maximum
+	for bound, exclusive := range map[string]string{"minimum": "exclusiveMinimum", "maximum": "exclusiveMaximum"} {
+		value := mappingValue(node, exclusive)
+		if value != nil && value.Kind == yaml.ScalarNode && value.Tag != "!!bool" {
+			removeKey(node, exclusive)
+			setKey(node, bound, value)
+			setKey(node, exclusive, scalarNode("true", "!!bool"))
+		}
+	}
+}
+
+// isNullSchema reports whether a schema only allows null, e.g. 'type: "null"'
+func isNullSchema(node *yaml.Node) bool {
+	types := mappingValue(node, "type")
+	if types == nil {
+		return false
+	}
+	if types.Kind == yaml.SequenceNode {
+		return len(types.Content) == 1 && types.Content[0].Value == "null"
+	}
+	return types.Value == "null"
+}
+
+// hoistDefs moves all schemas declared in $defs to components.schemas, since OpenAPI 3.0 has no local definitions.
+// References to them are rewritten accordingly
+func hoistDefs(document *yaml.Node) {
+	components := mappingValue(document, "components")
+	if components == nil {
+		components = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setKey(document, "components", components)
+	}
+	schemas := mappingValue(components, "schemas")
+	if schemas == nil {
+		schemas = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setKey(components, "schemas", schemas)
+	}
+
+	// we collect all definitions first, nested ones have to be found via their original pointer as well
+	type definition struct {
+		pointer string
+		name    string
+		schema  *yaml.Node
+	}
+	definitions := make([]definition, 0)
+	var collect func(node *yaml.Node, pointer string)
+	collect = func(node *yaml.Node, pointer string) {
+		node = resolveAlias(node)
+		if node == nil {
+			return
+		}
+		switch node.Kind {
+		case yaml.SequenceNode:
+			for idx, item := range node.Content {
+				collect(item, fmt.Sprintf("%s/%d", pointer, idx))
+			}
+		case yaml.MappingNode:
+			for _, name := range mappingKeys(mappingValue(node, "$defs")) {
+				definitions = append(definitions, definition{
+					pointer: pointer + "/$defs/" + encodeRefToken(name),
+					name:    name,
+					schema:  mappingValue(mappingValue(node, "$defs"), name),
+				})
+			}
+			for idx := 0; idx+1 < len(node.Content); idx += 2 {
+				collect(node.Content[idx+1], pointer+"/"+encodeRefToken(node.Content[idx].Value))
+			}
+		}
+	}
+	collect(document, "#")
+	if len(definitions) == 0 {
+		return
+	}
+
+	moved := make(map[string]string)
+	for _, def := range definitions {
+		// definitions are local, so they might share a name with a component
+		name := def.name
+		for idx := 2; mappingValue(schemas, name) != nil; idx++ {
+			name = fmt.Sprintf("%s%d", def.name, idx)
+		}
+		setKey(schemas, name, def.schema)
+		moved[def.pointer] = componentPointer("schemas", name)
+	}
+	removeDefs(document)
+
+	var rewrite func(node *yaml.Node)
+	rewrite = func(node *yaml.Node) {
+		if node == nil || node.Kind == yaml.AliasNode {
+			return
+		}
+		if ref := mappingValue(node, "$ref"); ref != nil {
+			// the reference might point into a definition, e.g. '#/$defs/Pet/properties/tag', the most specific one wins
+			longest := ""
+			for pointer := range moved {
+				if (ref.Value == pointer || strings.HasPrefix(ref.Value, pointer+"/")) && len(pointer) > len(longest) {
+					longest = pointer
+				}
+			}
+			if longest != "" {
+				ref.Value = moved[longest] + strings.TrimPrefix(ref.Value, longest)
+			}
+		}
+		for _, child := range node.Content {
+			rewrite(child)
+		}
+	}
+	rewrite(document)
+}
+
+func removeDefs(node *yaml.Node) {
+	if node == nil || node.Kind == yaml.AliasNode {
+		return
+	}
+	if node.Kind == yaml.MappingNode {
+		removeKey(node, "$defs")
+	}
+	for _, child := range node.Content {
+		removeDefs(child)
+	}
+}
+
+func scalarNode(value string, tag string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
+
+// setKey sets the value of key in a YAML mapping, keys that are not there yet are appended
+func setKey(node *yaml.Node, key string, value *yaml.Node) {
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		if node.Content[idx].Value == key {
+			node.Content[idx+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, scalarNode(key, "!!str"), value)
+}
+
+// removeKey removes key from a YAML mapping, if it is there
+func removeKey(node *yaml.Node, key string) {
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		if node.Content[idx].Value == key {
+			node.Content = append(node.Content[:idx], node.Content[idx+2:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"testing"
+)
+
+// oas31Document wraps a schema into a minimal OpenAPI document of the given version
+func oas31Document(version string, schemas string) string {
+	return "openapi: " + version + "\ninfo: {title: test, version: '1'}\npaths: {}\ncomponents:\n  schemas:\n" + schemas
+}
+
+func TestNormalizeOas31(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas string
+		want    string
+	}{
+		{
+			name:    "nullable type",
+			schemas: "    Name: {type: [string, 'null']}\n",
+			want:    "    Name: {nullable: true, type: string}\n",
+		},
+		{
+			name:    "null type",
+			schemas: "    Nothing: {type: 'null'}\n",
+			want:    "    Nothing: {nullable: true}\n",
+		},
+		{
+			name:    "multiple types",
+			schemas: "    Id: {type: [string, integer]}\n",
+			want:    "    Id: {oneOf: [{type: string}, {type: integer}]}\n",
+		},
+		{
+			name:    "nullable reference",
+			schemas: "    Pet: {type: object}\n    MaybePet: {oneOf: [{$ref: '#/components/schemas/Pet'}, {type: 'null'}]}\n",
+			want:    "    Pet: {type: object}\n    MaybePet: {nullable: true, allOf: [{$ref: '#/components/schemas/Pet'}]}\n",
+		},
+		{
+			name:    "const",
+			schemas: "    Kind: {type: string, const: dog}\n",
+			want:    "    Kind: {type: string, enum: [dog]}\n",
+		},
+		{
+			name:    "examples",
+			schemas: "    Name: {type: string, examples: [Rex, Bello]}\n",
+			want:    "    Name: {type: string, example: Rex}\n",
+		},
+		{
+			name:    "exclusive bounds",
+			schemas: "    Age: {type: integer, exclusiveMinimum: 0}\n",
+			want:    "    Age: {type: integer, minimum: 0, exclusiveMinimum: true}\n",
+		},
+		{
+			name:    "keywords as names and values",
+			schemas: "    Pet: {type: object, properties: {const: {type: string}}, example: {type: [a, 'null']}}\n",
+			want:    "    Pet: {type: object, properties: {const: {type: string}}, example: {type: [a, 'null']}}\n",
+		},
+		{
+			name:    "defs",
+			schemas: "    Pet: {type: object, properties: {tag: {$ref: '#/components/schemas/Pet/$defs/Tag'}}, $defs: {Tag: {type: string}}}\n",
+			want:    "    Pet: {type: object, properties: {tag: {$ref: '#/components/schemas/Tag'}}}\n    Tag: {type: string}\n",
+		},
+	}
+	for _, tt := range tests {
+		normalized, err := normalizeOas31([]byte(oas31Document("3.1.0", tt.schemas)))
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		var got, want interface{}
+		if err := yaml.Unmarshal(normalized, &got); err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if err := yaml.Unmarshal([]byte(oas31Document(oas30Version, tt.want)), &want); err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got\n%s", tt.name, normalized)
+		}
+	}
+}
+
+func TestNormalizeOas31Webhooks(t *testing.T) {
+	data := "openapi: 3.1.0\ninfo: {title: test, version: '1'}\njsonSchemaDialect: https://spec.openapis.org/oas/3.1/dialect/base\nwebhooks:\n  newPet: {post: {responses: {'200': {description: ok}}}}\n"
+	normalized, err := normalizeOas31([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := map[string]interface{}{}
+	if err := yaml.Unmarshal(normalized, &document); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"webhooks", "jsonSchemaDialect"} {
+		if _, ok := document[key]; ok {
+			t.Errorf("%s is not removed", key)
+		}
+	}
+	// paths are optional since 3.1, but required in 3.0
+	if _, ok := document["paths"]; !ok {
+		t.Errorf("paths are not added")
+	}
+}
+
+func TestNormalizeOas31KeepsOtherVersions(t *testing.T) {
+	data := oas31Document("3.0.3", "    Name: {type: string, const: dog}\n")
+	normalized, err := normalizeOas31([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(normalized) != data {
+		t.Errorf("3.0 document was changed to\n%s", normalized)
+	}
+}
@@ -0,0 +1,165 @@
+package parser
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+// OperationRoot is the GraphQL root type an OpenAPI operation is added to
+type OperationRoot string
+
+const (
+	RootQuery    OperationRoot = "query"
+	RootMutation OperationRoot = "mutation"
+	// RootSkip operations are not converted at all
+	RootSkip OperationRoot = "skip"
+)
+
+// ParameterPolicy decides what happens with the parameters of a location, e.g. all header parameters
+type ParameterPolicy string
+
+const (
+	// PolicyArgument adds every parameter as argument of its own
+	PolicyArgument ParameterPolicy = "argument"
+	// PolicyGroup moves all parameters of a location into a single input object argument, e.g. headers: GetPetHeadersInput
+	PolicyGroup ParameterPolicy = "group"
+	// PolicyHide leaves the parameters out, the GraphQL server has to take care of them
+	PolicyHide ParameterPolicy = "hide"
+)
+
+// NoContent decides what operations return whose response has no body, e.g. 204 No Content
+type NoContent string
+
+const (
+	// NoContentBoolean returns true once the request succeeded
+	NoContentBoolean NoContent = "boolean"
+	// NoContentVoid returns the custom scalar Void, which is always null
+	NoContentVoid NoContent = "void"
+	// NoContentPayload returns a type with the status code and the documented response headers, e.g. DeleteUserPayload
+	NoContentPayload NoContent = "payload"
+)
+
+// Ordering decides the order everything is rendered in
+type Ordering string
+
+const (
+	// OrderByName sorts paths, schemas and properties by name
+	OrderByName Ordering = "name"
+	// OrderBySource keeps the order paths, schemas and properties are declared in the OpenAPI document
+	OrderBySource Ordering = "source"
+)
+
+// Options configure how the OpenAPI spec is converted
+type Options struct {
+	// GenerateInterfaces declares a GraphQL interface for every schema that is extended via allOf,
+	// which all extending types implement
+	GenerateInterfaces bool
+	// Scalars maps OpenAPI formats to the GraphQL scalar used for them, e.g. "date-time" -> "DateTime".
+	// Custom scalars are declared automatically, formats not in here are converted to their base type
+	Scalars map[string]string
+	// OperationRoots maps the HTTP methods, e.g. "PATCH", to the root type their operations are added to.
+	// Methods not in here are skipped
+	OperationRoots map[string]OperationRoot
+	// ParameterPolicies maps the parameter locations, e.g. "header", to the policy used for their parameters.
+	// Locations not in here are hidden
+	ParameterPolicies map[string]ParameterPolicy
+	// HiddenHeaders are header parameters that are always hidden, regardless of the policy, and response headers
+	// that are never converted. They are compared case-insensitive, as HTTP does
+	HiddenHeaders []string
+	// MediaTypes are the media types responses and request bodies are converted from, the first documented one wins.
+	// They may be ranges, e.g. application/*+json. If none matches, responses fall back to String
+	MediaTypes []string
+	// ResponseSelector picks the response an operation returns, e.g. the 200 one
+	ResponseSelector ResponseSelector
+	// NoContent is what operations without response body return, GraphQL fields must have a type
+	NoContent NoContent
+	// ResponsePayloads wraps every successful response into a type holding the status code, the body as data and
+	// the documented response headers, e.g. ListPetsPayload { status: Int!, data: [Pet], xTotalCount: Int }
+	ResponsePayloads bool
+	// ResultUnions lets operations return a union of all their documented responses, e.g. GetUserResult = User | NotFoundError,
+	// instead of only the successful one. Error responses become types carrying the status code and the body
+	ResultUnions bool
+	// Timestamp stamps the generation time into the spec. Without it, converting the same OpenAPI spec
+	// always results in the exact same GraphQL spec, which keeps diffs of generated files clean
+	Timestamp bool
+	// Ordering decides whether paths, schemas and properties are sorted by name or kept in their declaration order.
+	// Either way the output is deterministic
+	Ordering Ordering
+}
+
+// DefaultOperationRoots returns the method classification used by DefaultOptions.
+// Reading methods are queries, writing ones mutations, and the ones only interesting on transport level are skipped
+func DefaultOperationRoots() map[string]OperationRoot {
+	return map[string]OperationRoot{
+		string(oasGet):     RootQuery,
+		string(oasHead):    RootSkip,
+		string(oasOptions): RootSkip,
+		string(oasTrace):   RootSkip,
+		string(oasConnect): RootSkip,
+		string(oasDelete):  RootMutation,
+		string(oasPost):    RootMutation,
+		string(oasPut):     RootMutation,
+		string(oasPatch):   RootMutation,
+	}
+}
+
+// DefaultParameterPolicies returns the parameter policies used by DefaultOptions.
+// Path and query parameters are what the API is about, headers are grouped and cookies are a matter of the server
+func DefaultParameterPolicies() map[string]ParameterPolicy {
+	return map[string]ParameterPolicy{
+		openapi3.ParameterInPath:   PolicyArgument,
+		openapi3.ParameterInQuery:  PolicyArgument,
+		openapi3.ParameterInHeader: PolicyGroup,
+		openapi3.ParameterInCookie: PolicyHide,
+	}
+}
+
+// DefaultHiddenHeaders returns the well known transport headers used by DefaultOptions,
+// which are set by the GraphQL server or HTTP client and have nothing to do with the API itself
+func DefaultHiddenHeaders() []string {
+	return []string{
+		"Accept",
+		"Accept-Encoding",
+		"Accept-Language",
+		"Authorization",
+		"Connection",
+		"Content-Length",
+		"Content-Type",
+		"Cookie",
+		"Host",
+		"Proxy-Authorization",
+		"User-Agent",
+		"X-Correlation-Id",
+		"X-Request-Id",
+	}
+}
+
+// DefaultScalars returns the format to scalar mapping used by DefaultOptions
+func DefaultScalars() map[string]string {
+	return map[string]string{
+		"date-time": "DateTime",
+		"date":      "Date",
+		"time":      "Time",
+		"uuid":      "UUID",
+		"uri":       "URI",
+		"url":       "URI",
+		"byte":      "Base64",
+		"binary":    "Upload",
+		"int64":     "Long",
+	}
+}
+
+// DefaultOptions are the Options used by Parse
+func DefaultOptions() Options {
+	return Options{
+		GenerateInterfaces: false,
+		Scalars:            DefaultScalars(),
+		OperationRoots:     DefaultOperationRoots(),
+		ParameterPolicies:  DefaultParameterPolicies(),
+		HiddenHeaders:      DefaultHiddenHeaders(),
+		MediaTypes:         DefaultMediaTypes(),
+		ResponseSelector:   SuccessResponseSelector{},
+		NoContent:          NoContentBoolean,
+		ResponsePayloads:   false,
+		ResultUnions:       false,
+		Timestamp:          false,
+		Ordering:           OrderByName,
+	}
+}
@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/FrauElster/gopenApiToGraphQL/util"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// interfaceSuffix is appended to the name of a schema extended via allOf, because the schema itself stays a type
+const interfaceSuffix = "Interface"
+
+// aliasedRef returns the referenced schema if the schema is just a wrapper around a single reference,
+// e.g. 'allOf: [$ref: Pet]' which is often used to add a description or nullable to a reference
+func aliasedRef(schemaRef *openapi3.SchemaRef) *openapi3.SchemaRef {
+	schema := schemaRef.Value
+	if schemaRef.Ref == "" && len(schema.AllOf) == 1 && schema.AllOf[0].Ref != "" && len(schema.Properties) == 0 {
+		return schema.AllOf[0]
+	}
+	return schemaRef
+}
+
+// flattenAllOf merges all allOf schemas into a single object schema, the schema itself is returned if it has no allOf.
+// Properties of later schemas override previous ones and the schemas own properties override all of them
+func (c *converter) flattenAllOf(schema *openapi3.Schema) *openapi3.Schema {
+	if len(schema.AllOf) == 0 {
+		return schema
+	}
+	// the flattened schema is used as identity for declared types, so it has to be the same every time
+	if flattened, ok := c.flattened[schema]; ok {
+		return flattened
+	}
+
+	flattened := *schema
+	flattened.AllOf = nil
+	flattened.Type = "object"
+	flattened.Properties = make(openapi3.Schemas)
+	flattened.Required = make([]string, 0)
+
+	merge := func(part *openapi3.Schema) {
+		for _, propertyName := range c.propertyNames(part) {
+			flattened.Properties[propertyName] = part.Properties[propertyName]
+			if c.order != nil && !util.IsInSlice(propertyName, c.order.properties[&flattened]) {
+				c.order.properties[&flattened] = append(c.order.properties[&flattened], propertyName)
+			}
+		}
+		for _, required := range part.Required {
+			if !util.IsInSlice(required, flattened.Required) {
+				flattened.Required = append(flattened.Required, required)
+			}
+		}
+		if flattened.Description == "" {
+			flattened.Description = part.Description
+		}
+	}
+	for _, part := range schema.AllOf {
+		// parts might be composed themselves
+		merge(c.flattenAllOf(part.Value))
+	}
+	merge(schema)
+
+	c.flattened[schema] = &flattened
+	return &flattened
+}
+
+// allOfInterfaces returns the interfaces a type implements, which are all schemas it extends via allOf.
+// Extending is transitive, if B extends A and C extends B, C implements A and B
+func (c *converter) allOfInterfaces(schema *openapi3.Schema) ([]string, error) {
+	interfaces := make([]string, 0)
+	if !c.opts.GenerateInterfaces {
+		return interfaces, nil
+	}
+
+	add := func(names ...string) {
+		for _, name := range names {
+			if !util.IsInSlice(name, interfaces) {
+				interfaces = append(interfaces, name)
+			}
+		}
+	}
+	for _, part := range schema.AllOf {
+		// only named schemas become interfaces, anonymous parts are just merged into the type
+		if name, ok := c.namedSchema(part); ok && isObjectSchema(c.flattenAllOf(part.Value)) {
+			interfaceName, err := c.interfaceConversion(name, part.Value)
+			if err != nil {
+				return nil, err
+			}
+			add(interfaceName)
+		}
+
+		inherited, err := c.allOfInterfaces(part.Value)
+		if err != nil {
+			return nil, err
+		}
+		add(inherited...)
+	}
+	return interfaces, nil
+}
+
+// interfaceConversion declares a GraphQL interface for a named schema which is extended by others and returns its name
+func (c *converter) interfaceConversion(baseName string, schema *openapi3.Schema) (string, error) {
+	if interfaceName, ok := c.interfaceNames[baseName]; ok {
+		return interfaceName, nil
+	}
+	interfaceName := c.symbols.declareType(baseName+interfaceSuffix, symbolInterface, baseName)
+	c.interfaceNames[baseName] = interfaceName
+
+	// the interface has exactly the fields of the base type, so we reuse its conversion
+	baseType, err := c.namedTypeConversion(baseName, schema)
+	if err != nil {
+		return "", err
+	}
+
+	var discriminator *GqlDiscriminator
+	if schema.Discriminator != nil {
+		discriminator = &GqlDiscriminator{PropertyName: schema.Discriminator.PropertyName, Mapping: make(map[string]string)}
+		for value, ref := range schema.Discriminator.Mapping {
+			discriminator.Mapping[value] = c.refName(ref)
+		}
+	}
+
+	c.addInterface(GqlInterface{
+		Name:          interfaceName,
+		Description:   baseType.Description,
+		Base:          baseName,
+		Attributes:    baseType.Attributes,
+		Discriminator: discriminator,
+	})
+	return interfaceName, nil
+}
+
+// linkInterfaces lets every base type implement its own interface, which we only know once everything is converted.
+// Types that override an inherited property with another type can't implement the interface, they only extend the schema.
+// Discriminators without explicit mapping use the names of the implementing types as value
+func (c *converter) linkInterfaces() {
+	for typeIdx := range c.types {
+		gqlType := &c.types[typeIdx]
+		gqlType.Interfaces = util.FilterSlice(gqlType.Interfaces, func(interfaceName string) bool {
+			return c.implementsInterface(*gqlType, interfaceName)
+		})
+	}
+
+	for idx := range c.interfaces {
+		iface := &c.interfaces[idx]
+		for typeIdx := range c.types {
+			gqlType := &c.types[typeIdx]
+			if gqlType.Name == iface.Base {
+				gqlType.Interfaces = append([]string{iface.Name}, gqlType.Interfaces...)
+			}
+		}
+
+		if iface.Discriminator != nil && len(iface.Discriminator.Mapping) == 0 {
+			for _, gqlType := range c.types {
+				if util.IsInSlice(iface.Name, gqlType.Interfaces) && gqlType.Name != iface.Base {
+					iface.Discriminator.Mapping[gqlType.Name] = gqlType.Name
+				}
+			}
+		}
+	}
+}
+
+// implementsInterface reports whether a type has every field of the interface with the same type, which GraphQL
+// requires for implementing it. Types that don't are reported
+func (c *converter) implementsInterface(gqlType GqlType, interfaceName string) bool {
+	for _, iface := range c.interfaces {
+		if iface.Name != interfaceName {
+			continue
+		}
+		for _, ifaceField := range iface.Attributes {
+			var field *GqlAttribute
+			for idx := range gqlType.Attributes {
+				if gqlType.Attributes[idx].Name == ifaceField.Name {
+					field = &gqlType.Attributes[idx]
+				}
+			}
+			switch {
+			case field == nil:
+				c.symbols.rename(gqlType.Name, fmt.Sprintf("does not implement %s, field %s is missing", interfaceName, ifaceField.Name))
+				return false
+			// a required field may implement an optional one, but not the other way around
+			case field.Type != ifaceField.Type || (ifaceField.IsRequired && !field.IsRequired):
+				c.symbols.rename(gqlType.Name, fmt.Sprintf("does not implement %s, field %s has type %s instead of %s",
+					interfaceName, field.Name, gqlFieldType(*field), gqlFieldType(ifaceField)))
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// gqlFieldType returns the type of an attribute as written in GraphQL, e.g. String!
+func gqlFieldType(attribute GqlAttribute) string {
+	if attribute.IsRequired {
+		return attribute.Type + "!"
+	}
+	return attribute.Type
+}
@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const overrideSpec = `openapi: 3.0.3
+info: {title: override, version: "1"}
+paths: {}
+components:
+  schemas:
+    Base: {type: object, properties: {v: {type: string}}}
+    Child:
+      allOf:
+        - $ref: '#/components/schemas/Base'
+        - {type: object, properties: {v: {type: integer}}}
+    Sibling:
+      allOf:
+        - $ref: '#/components/schemas/Base'
+        - {type: object, properties: {w: {type: integer}}}
+`
+
+func TestOverriddenPropertyDoesNotImplementInterface(t *testing.T) {
+	opts := DefaultOptions()
+	opts.GenerateInterfaces = true
+	spec, schema := parseSpec(t, overrideSpec, opts)
+
+	if len(schema.Types["Child"].Interfaces) != 0 {
+		t.Errorf("Child implements %v, but overrides v", schema.Types["Child"].Interfaces)
+	}
+	if len(schema.Types["Sibling"].Interfaces) != 1 {
+		t.Errorf("Sibling implements %v, want BaseInterface", schema.Types["Sibling"].Interfaces)
+	}
+	found := false
+	for _, diagnostic := range spec.Diagnostics {
+		found = found || strings.Contains(diagnostic.String(), "Child: does not implement BaseInterface")
+	}
+	if !found {
+		t.Errorf("no diagnostic for Child in %v", spec.Diagnostics)
+	}
+}
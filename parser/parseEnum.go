@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// isEnumSchema reports whether a schema can be represented as GraphQL enum.
+// Booleans are technically an enum as well, but Boolean is the better fit for them.
+// Enums of nothing but null have no GraphQL value, they are converted as their base type instead
+func isEnumSchema(schema *openapi3.Schema) bool {
+	if schema.Type == string(oasBool) {
+		return false
+	}
+	for _, value := range schema.Enum {
+		if value != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// enumConversion declares a GraphQL enum for the schema and returns its name.
+// Named enums keep the component name, anonymous ones get the given baseName
+func (c *converter) enumConversion(schema *openapi3.Schema, name string, isNamed bool) string {
+	// enums are used for input and output alike, so the same schema may be visited multiple times
+	if enumName, ok := c.enumNames[schema]; ok {
+		return enumName
+	}
+	if !isNamed {
+		name = c.symbols.declareType(name, symbolEnum, name)
+	}
+	c.enumNames[schema] = name
+
+	descriptions := enumDescriptions(schema)
+	values := make([]GqlEnumValue, 0, len(schema.Enum))
+	taken := make(map[string]bool)
+	for idx, wireValue := range schema.Enum {
+		// null is how OpenAPI allows a nullable enum, but it is no value in GraphQL
+		if wireValue == nil {
+			continue
+		}
+		// sanitizing could turn different values into the same name, e.g. "in-progress" and "IN_PROGRESS"
+		valueName := toEnumValueName(wireValue)
+		for idx := 2; taken[valueName]; idx++ {
+			valueName = fmt.Sprintf("%s_%d", toEnumValueName(wireValue), idx)
+		}
+		taken[valueName] = true
+		if valueName != toEnumValueName(wireValue) {
+			c.symbols.rename(name, fmt.Sprintf("enum value %v renamed to %s, the name is already used by another value", wireValue, valueName))
+		}
+		values = append(values, GqlEnumValue{Name: valueName, Description: descriptions(idx, wireValue), Value: wireValue})
+	}
+
+	c.addEnum(GqlEnum{Name: name, Description: schema.Description, Values: values})
+	return name
+}
+
+// enumDescriptions returns a lookup for the description of an enum value. OpenAPI has no way to describe enum values,
+// but there are two common extensions: an array "x-enum-descriptions" in the order of the values
+// and an object "x-enumDescriptions" mapping the values to their description
+func enumDescriptions(schema *openapi3.Schema) func(idx int, wireValue interface{}) string {
+	var descriptionList []string
+	decodeExtension(schema.ExtensionProps, "x-enum-descriptions", &descriptionList)
+	var descriptionMap map[string]string
+	decodeExtension(schema.ExtensionProps, "x-enumDescriptions", &descriptionMap)
+
+	return func(idx int, wireValue interface{}) string {
+		if idx < len(descriptionList) {
+			return descriptionList[idx]
+		}
+		return descriptionMap[fmt.Sprint(wireValue)]
+	}
+}
+
+var enumWordBoundaryReg = regexp.MustCompile("([a-z0-9])([A-Z])")
+var enumInvalidCharsReg = regexp.MustCompile("[^_0-9A-Za-z]+")
+
+// toEnumValueName converts any enum value to a valid GraphQL enum value in SCREAMING_SNAKE_CASE,
+// e.g. "in-progress" -> IN_PROGRESS, "inProgress" -> IN_PROGRESS, 1 -> _1
+func toEnumValueName(wireValue interface{}) string {
+	name := fmt.Sprint(wireValue)
+	name = enumWordBoundaryReg.ReplaceAllString(name, "${1}_${2}")
+	name = enumInvalidCharsReg.ReplaceAllString(name, "_")
+	name = strings.ToUpper(strings.Trim(name, "_"))
+
+	if name == "" {
+		return "EMPTY"
+	}
+	// names must not start with a digit
+	if unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name
+}
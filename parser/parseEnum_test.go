@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"testing"
+)
+
+func TestIsEnumSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *openapi3.Schema
+		want   bool
+	}{
+		{"values", &openapi3.Schema{Type: "string", Enum: []interface{}{"red", "green"}}, true},
+		{"nullable values", &openapi3.Schema{Type: "string", Nullable: true, Enum: []interface{}{"red", nil}}, true},
+		{"only null", &openapi3.Schema{Type: "string", Nullable: true, Enum: []interface{}{nil}}, false},
+		{"boolean", &openapi3.Schema{Type: "boolean", Enum: []interface{}{true}}, false},
+		{"no values", &openapi3.Schema{Type: "string"}, false},
+	}
+	for _, tt := range tests {
+		if got := isEnumSchema(tt.schema); got != tt.want {
+			t.Errorf("%s: isEnumSchema = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+const nullEnumSpec = `openapi: 3.0.3
+info: {title: null enums, version: "1"}
+paths: {}
+components:
+  schemas:
+    Nothing: {type: string, nullable: true, enum: [null]}
+    Thing:
+      type: object
+      properties:
+        nothing: {$ref: '#/components/schemas/Nothing'}
+        inline: {type: string, nullable: true, enum: [null]}
+`
+
+func TestNullEnumIsNoEnum(t *testing.T) {
+	_, schema := parseSpec(t, nullEnumSpec, DefaultOptions())
+	if got := schema.Types["Thing"].Fields.ForName("inline").Type.Name(); got != string(gqlString) {
+		t.Errorf("Thing.inline has type %s, want String", got)
+	}
+}
@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/FrauElster/gopenApiToGraphQL/util"
+	"github.com/getkin/kin-openapi/openapi3"
+	log "github.com/sirupsen/logrus"
+)
+
+// requestBodyArgument is the name of the argument the request body is passed with, following the GraphQL convention
+const requestBodyArgument = "input"
+
+// inputSuffix is appended to the name of a schema if we have to generate an input type for it,
+// because GraphQL does not allow output types to be used as arguments
+const inputSuffix = "Input"
+
+// parseRequestBody converts the OpenAPI requestBody of an operation to a GraphQL argument, which is returned with
+// the media type the body is sent as. It returns nil if the operation has no request body
+func (c *converter) parseRequestBody(oasOperation openapi3.Operation, operationName string) (*GqlAttribute, string, error) {
+	if oasOperation.RequestBody == nil {
+		return nil, "", nil
+	}
+	// if it is a reference to components.requestBodies, kin-openapi already resolved it for us
+	requestBody := oasOperation.RequestBody.Value
+	if requestBody == nil {
+		return nil, "", fmt.Errorf("unresolved request body %s", oasOperation.RequestBody.Ref)
+	}
+	// inline bodies are named after the operation, e.g. createPet -> CreatePetInput,
+	// shared ones after their component, e.g. '#/components/requestBodies/NewPet' -> NewPetInput
+	baseName := upperFirst(operationName)
+	if componentName, ok := componentRef(oasOperation.RequestBody.Ref, "requestBodies"); ok {
+		baseName = upperFirst(toTypeName(componentName))
+	}
+
+	mime, mediaType := c.requestBodyMediaType(requestBody.Content)
+	if mediaType == nil || mediaType.Schema == nil {
+		log.Warnf("%s - request body has no schema, skipping it", operationName)
+		return nil, "", nil
+	}
+
+	typeName, err := c.inputTypeConversion(mediaType.Schema, baseName)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not convert request body: %w", err)
+	}
+
+	return &GqlAttribute{
+		Name:        requestBodyArgument,
+		Description: requestBody.Description,
+		Type:        typeName,
+		IsRequired:  requestBody.Required,
+	}, mime, nil
+}
+
+// requestBodyMediaType picks the media type we generate the input from and returns it with the media type it is sent as
+func (c *converter) requestBodyMediaType(content openapi3.Content) (string, *openapi3.MediaType) {
+	// we prefer json, as it maps best to GraphQL
+	if mime, preferred := c.preferredMediaType(content); preferred != nil {
+		return mime, preferred
+	}
+
+	// forms are basically flat objects as well
+	for _, mime := range []string{"application/x-www-form-urlencoded", "multipart/form-data"} {
+		if formContent := content.Get(mime); formContent != nil && formContent.Schema != nil {
+			return mime, formContent
+		}
+	}
+
+	// anything else, as long as it has a schema. Sorted, so we take the same one every time
+	for _, mime := range util.SortedKeys(content) {
+		if content[mime].Schema != nil {
+			return mime, content[mime]
+		}
+	}
+	return "", nil
+}
+
+// inputTypeConversion returns the GraphQL type usable as argument for the given schema.
+// Objects will be registered as input types, named either after the referenced component or the given baseName
+func (c *converter) inputTypeConversion(schemaRef *openapi3.SchemaRef, baseName string) (string, error) {
+	schemaRef = aliasedRef(schemaRef)
+	// inputs have no interfaces, so allOf is simply merged
+	schema := c.flattenAllOf(schemaRef.Value)
+
+	// GraphQL has no input unions, the best we can do is to accept anything
+	if isUnionSchema(schema) {
+		log.Warnf("%s - unions are not supported as input, using scalar %s", baseName, gqlJSON)
+		return c.useScalar(gqlJSON), nil
+	}
+
+	// references to components are named after them, e.g. '#/components/schemas/Pet' -> PetInput
+	if name, ok := c.namedSchema(schemaRef); ok {
+		// named schemas without properties are declared as scalars (see Parse) or enums, those are valid input types already
+		if isScalarSchema(schema) {
+			return name, nil
+		}
+		baseName = name
+	} else {
+		baseName = c.refBaseName(schemaRef.Ref, baseName)
+	}
+
+	// enums can be used as input and output, so we share them with the output types
+	if isEnumSchema(schema) {
+		return c.enumConversion(schema, baseName, false), nil
+	}
+
+	switch schema.Type {
+	case "object":
+		if len(schema.Properties) == 0 {
+			// a free form object, there is nothing we can map its properties to
+			log.Warnf("%s - object without properties, defaulting to %s", baseName, gqlString)
+			return string(gqlString), nil
+		}
+		return c.namedInputConversion(baseName, schema)
+	case "array":
+		typeName, err := c.inputTypeConversion(schema.Items, baseName+"Item")
+		if err != nil {
+			return "", err
+		}
+		return listOf(typeName, schema.Items), nil
+	default:
+		return c.scalarConversion(schema)
+	}
+}
+
+// namedInputConversion registers an input type for an object schema, named baseName + inputSuffix
+func (c *converter) namedInputConversion(baseName string, schema *openapi3.Schema) (string, error) {
+	// the same schema may be used by multiple operations, or even by itself, so we only convert it once
+	if name, ok := c.inputNames[schema]; ok {
+		return name, nil
+	}
+	name := c.symbols.declareType(baseName+inputSuffix, symbolInput, baseName)
+	c.inputNames[schema] = name
+	input := &GqlInput{Name: name, Description: schema.Description}
+	c.addInput(input)
+
+	attributes := make([]GqlAttribute, 0, len(schema.Properties))
+	taken := make(map[string]bool)
+	for _, propertyName := range c.propertyNames(schema) {
+		attributeName := c.symbols.declareAttribute(taken, toFieldName(propertyName), propertyName, name+"."+propertyName)
+
+		// nested inline objects are named after their parent, e.g. CreatePetInput.meta -> CreatePetMetaInput
+		property := schema.Properties[propertyName]
+		typeName, err := c.inputTypeConversion(property, baseName+upperFirst(attributeName))
+		if err != nil {
+			return "", fmt.Errorf("could not convert %s.%s: %w", name, propertyName, err)
+		}
+		isRequired := isRequiredProperty(schema, propertyName, property)
+		attributes = append(attributes, GqlAttribute{
+			Name:        attributeName,
+			WireName:    propertyName,
+			Description: propertyDescription(property),
+			Type:        typeName,
+			IsRequired:  isRequired,
+			Directives: argumentDeprecation(name+"."+propertyName, isRequired,
+				deprecation(property.Value.Deprecated, property.Value.ExtensionProps, property.Value.Description)),
+		})
+	}
+	input.Attributes = attributes
+
+	return name, nil
+}
+
+// isScalarSchema reports whether a named schema ends up as a GraphQL scalar or enum, because it has no attributes
+func isScalarSchema(schema *openapi3.Schema) bool {
+	return !isObjectSchema(schema)
+}
@@ -0,0 +1,27 @@
+package parser
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"testing"
+)
+
+func TestOperationDescription(t *testing.T) {
+	tests := []struct {
+		summary     string
+		description string
+		want        string
+	}{
+		{"", "", ""},
+		{"Get a user", "", "Get a user"},
+		{"", "Returns the user", "Returns the user"},
+		{"Get a user", "Get a user", "Get a user"},
+		{" Get a user ", "Get a user\n", "Get a user"},
+		{"Get a user", "Returns the user by id", "Get a user\n\nReturns the user by id"},
+	}
+	for _, tt := range tests {
+		got := operationDescription(openapi3.Operation{Summary: tt.summary, Description: tt.description})
+		if got != tt.want {
+			t.Errorf("operationDescription(%q, %q) = %q, want %q", tt.summary, tt.description, got, tt.want)
+		}
+	}
+}
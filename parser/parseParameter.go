@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	log "github.com/sirupsen/logrus"
+	"strings"
+)
+
+// parameterLocations are all locations a parameter can be in, grouped parameters are added as arguments in this order
+var parameterLocations = []string{
+	openapi3.ParameterInPath,
+	openapi3.ParameterInQuery,
+	openapi3.ParameterInHeader,
+	openapi3.ParameterInCookie,
+}
+
+// groupArguments are the names of the arguments grouped parameters are passed with, e.g. headers: GetPetHeadersInput
+var groupArguments = map[string]string{
+	openapi3.ParameterInPath:   "path",
+	openapi3.ParameterInQuery:  "query",
+	openapi3.ParameterInHeader: "headers",
+	openapi3.ParameterInCookie: "cookies",
+}
+
+// mergeParameters returns the parameters of an operation, including the ones declared for all operations of its path.
+// Parameters are identified by name and location, an operation may override a path parameter by redeclaring it
+func mergeParameters(pathParameters openapi3.Parameters, operationParameters openapi3.Parameters) openapi3.Parameters {
+	merged := make(openapi3.Parameters, 0, len(pathParameters)+len(operationParameters))
+	for _, pathParameter := range pathParameters {
+		if pathParameter.Value != nil && operationParameters.GetByInAndName(pathParameter.Value.In, pathParameter.Value.Name) != nil {
+			// overridden, the operation parameter is added below
+			continue
+		}
+		merged = append(merged, pathParameter)
+	}
+	return append(merged, operationParameters...)
+}
+
+// parseParameters maps the parameters to GqlAttribute s, according to the ParameterPolicy of their location.
+// reserved are argument names already in use, e.g. for the request body
+func (c *converter) parseParameters(parameters openapi3.Parameters, operationName string, reserved []string) ([]GqlAttribute, error) {
+	gqlParams := make([]GqlAttribute, 0, len(parameters))
+	groups := make(map[string][]GqlAttribute)
+
+	taken := make(map[string]bool)
+	for _, name := range reserved {
+		taken[name] = true
+	}
+	for location, policy := range c.opts.ParameterPolicies {
+		if policy == PolicyGroup {
+			taken[groupArguments[location]] = true
+		}
+	}
+
+	for _, paramRef := range parameters {
+		oasParam := paramRef.Value
+		if oasParam == nil {
+			return nil, fmt.Errorf("unresolved parameter %s", paramRef.Ref)
+		}
+
+		policy := c.parameterPolicy(oasParam)
+		if policy == PolicyHide {
+			log.Debugf("%s - hiding %s parameter %s", operationName, oasParam.In, oasParam.Name)
+			continue
+		}
+
+		gqlParam, err := c.parameterConversion(paramRef, operationName)
+		if err != nil {
+			return nil, err
+		}
+		if policy == PolicyGroup {
+			groups[oasParam.In] = append(groups[oasParam.In], gqlParam)
+			continue
+		}
+
+		// the same name may be used in different locations, e.g. path and query id, which we tell apart by location
+		if taken[gqlParam.Name] {
+			unique := gqlParam.Name + upperFirst(oasParam.In)
+			for idx := 2; taken[unique]; idx++ {
+				unique = fmt.Sprintf("%s%s%d", gqlParam.Name, upperFirst(oasParam.In), idx)
+			}
+			c.symbols.rename(fmt.Sprintf("%s(%s)", operationName, oasParam.Name),
+				fmt.Sprintf("%s parameter %s renamed to %s, the name is already used by another argument", oasParam.In, oasParam.Name, unique))
+			gqlParam.Name = unique
+		}
+		taken[gqlParam.Name] = true
+		gqlParams = append(gqlParams, gqlParam)
+	}
+
+	for _, location := range parameterLocations {
+		if len(groups[location]) > 0 {
+			gqlParams = append(gqlParams, c.parameterGroup(operationName, location, groups[location]))
+		}
+	}
+	return gqlParams, nil
+}
+
+// parameterPolicy returns how a parameter is handled, well known transport headers are always hidden
+func (c *converter) parameterPolicy(oasParam *openapi3.Parameter) ParameterPolicy {
+	if oasParam.In == openapi3.ParameterInHeader && c.isHiddenHeader(oasParam.Name) {
+		return PolicyHide
+	}
+	if policy, ok := c.opts.ParameterPolicies[oasParam.In]; ok {
+		return policy
+	}
+	return PolicyHide
+}
+
+func (c *converter) isHiddenHeader(name string) bool {
+	for _, hidden := range c.opts.HiddenHeaders {
+		if strings.EqualFold(hidden, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parameterConversion converts a single parameter to a GqlAttribute
+func (c *converter) parameterConversion(paramRef *openapi3.ParameterRef, operationName string) (GqlAttribute, error) {
+	oasParam := paramRef.Value
+	paramSchema := oasParam.Schema
+	if paramSchema == nil || paramSchema.Value == nil {
+		// parameters may use content instead of a schema, which we take as plain string
+		paramSchema = openapi3.NewStringSchema().NewRef()
+		if _, mediaType := c.requestBodyMediaType(oasParam.Content); mediaType != nil {
+			paramSchema = mediaType.Schema
+		}
+	}
+
+	// parameters are arguments, so objects have to become input types. References are named after the component,
+	// anonymous ones after the operation and parameter, e.g. listPets(filter) -> ListPetsFilterInput.
+	// Parameters shared via components.parameters are named after the component, e.g. Filter -> FilterInput
+	name := toCamelName(oasParam.Name)
+	baseName := upperFirst(operationName) + upperFirst(name)
+	if componentName, ok := componentRef(paramRef.Ref, "parameters"); ok {
+		baseName = upperFirst(toTypeName(componentName))
+	}
+	typeName, err := c.inputTypeConversion(paramSchema, baseName)
+	if err != nil {
+		return GqlAttribute{}, fmt.Errorf("could not convert type in %s: %w", oasParam.Name, err)
+	}
+	return GqlAttribute{
+		Name:        name,
+		WireName:    oasParam.Name,
+		Description: oasParam.Description,
+		Type:        typeName,
+		IsRequired:  oasParam.Required,
+		Directives:  argumentDeprecation(oasParam.Name, oasParam.Required, deprecation(oasParam.Deprecated, oasParam.ExtensionProps, oasParam.Description)),
+	}, nil
+}
+
+// parameterGroup declares an input type holding all grouped parameters of a location and returns the argument
+// it is passed with, e.g. headers: GetPetHeadersInput. The argument is required if any of the parameters is
+func (c *converter) parameterGroup(operationName string, location string, params []GqlAttribute) GqlAttribute {
+	argumentName := groupArguments[location]
+	origin := fmt.Sprintf("%s(%s)", operationName, argumentName)
+	name := c.symbols.declareType(upperFirst(operationName)+upperFirst(argumentName)+inputSuffix, symbolInput, origin)
+
+	isRequired := false
+	taken := make(map[string]bool)
+	for idx := range params {
+		params[idx].Name = c.symbols.declareAttribute(taken, params[idx].Name, params[idx].WireName, origin)
+		isRequired = isRequired || params[idx].IsRequired
+	}
+	c.addInput(&GqlInput{
+		Name:        name,
+		Description: fmt.Sprintf("The %s parameters of %s", location, operationName),
+		Attributes:  params,
+	})
+
+	return GqlAttribute{
+		Name:       argumentName,
+		Type:       name,
+		IsRequired: isRequired,
+	}
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// voidScalar is the scalar returned by operations without content if Options.NoContent is NoContentVoid
+const voidScalar = "Void"
+
+// dataField is the field payload types carry the body of the response in
+const dataField = "data"
+
+// noContentType returns the type of an operation whose response has no body, as configured in Options.NoContent.
+// code and response are the ones that have no content, they are empty if the operation documents no response at all
+func (c *converter) noContentType(operationName string, code string, response *openapi3.Response) (string, error) {
+	if c.opts.ResponsePayloads || c.opts.NoContent == NoContentPayload {
+		return c.payloadType(upperFirst(operationName)+"Payload", operationName, code, "", response)
+	}
+	if c.opts.NoContent == NoContentVoid {
+		return c.useScalar(voidScalar), nil
+	}
+	return string(gqlBoolean), nil
+}
+
+// payloadType declares a type wrapping a response and returns its name. It holds the status code, the body as data
+// and the documented response headers, e.g. ListPetsPayload { status: Int!, data: [Pet], xTotalCount: Int }.
+// Responses without content have no data, dataType is empty then
+func (c *converter) payloadType(baseName string, operationName string, code string, dataType string, response *openapi3.Response) (string, error) {
+	name := c.symbols.declareType(baseName, symbolType, operationName)
+
+	description := fmt.Sprintf("The API responded to %s without content", operationName)
+	if code != "" {
+		description = fmt.Sprintf("The API responded with %s", statusDescription(code))
+	}
+	attributes := []GqlAttribute{statusAttribute()}
+	taken := map[string]bool{statusField: true}
+	if dataType != "" {
+		attributes = append(attributes, GqlAttribute{Name: dataField, Description: "The body of the response", Type: dataType})
+		taken[dataField] = true
+	}
+	headers, err := c.responseHeaders(name, response, taken)
+	if err != nil {
+		return "", err
+	}
+	c.addType(GqlType{
+		Name:        name,
+		Description: description,
+		Type:        "object",
+		Attributes:  append(attributes, headers...),
+	})
+	return name, nil
+}
+
+// responseHeaders converts the documented headers of a response to attributes of the type typeName,
+// e.g. X-Total-Count -> xTotalCount: Int. taken holds the attribute names already used in the type.
+// Headers in Options.HiddenHeaders are left out, the response is allowed to be nil
+func (c *converter) responseHeaders(typeName string, response *openapi3.Response, taken map[string]bool) ([]GqlAttribute, error) {
+	attributes := make([]GqlAttribute, 0)
+	if response == nil {
+		return attributes, nil
+	}
+	for _, headerName := range c.headerNames(response) {
+		headerRef := response.Headers[headerName]
+		if headerRef == nil || headerRef.Value == nil {
+			return nil, fmt.Errorf("unresolved header %s", headerName)
+		}
+		if c.isHiddenHeader(headerName) {
+			continue
+		}
+		header := headerRef.Value
+
+		// headers may use content instead of a schema, just like parameters
+		headerSchema := header.Schema
+		if headerSchema == nil || headerSchema.Value == nil {
+			headerSchema = openapi3.NewStringSchema().NewRef()
+			if _, mediaType := c.requestBodyMediaType(header.Content); mediaType != nil {
+				headerSchema = mediaType.Schema
+			}
+		}
+
+		// anonymous header schemas are named after the type and header, shared ones after their component
+		name := toCamelName(headerName)
+		baseName := typeName + upperFirst(name)
+		if componentName, ok := componentRef(headerRef.Ref, "headers"); ok {
+			baseName = upperFirst(toTypeName(componentName))
+		}
+		headerType, err := c.schemaRefConversion(headerSchema, baseName)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert type in header %s: %w", headerName, err)
+		}
+		attributes = append(attributes, GqlAttribute{
+			Name:        c.symbols.declareAttribute(taken, name, headerName, typeName),
+			WireName:    headerName,
+			Description: header.Description,
+			Type:        headerType,
+			IsRequired:  header.Required,
+			Directives:  deprecation(header.Deprecated, header.ExtensionProps, header.Description),
+		})
+	}
+	return attributes, nil
+}
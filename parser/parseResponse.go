@@ -0,0 +1,273 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/FrauElster/gopenApiToGraphQL/util"
+	"github.com/getkin/kin-openapi/openapi3"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// statusField is the field synthetic result types carry the HTTP status code in
+const statusField = "status"
+
+// rangeStatusNames name the status code ranges OpenAPI allows instead of single codes
+var rangeStatusNames = map[string]string{
+	"1XX":     "Informational",
+	"2XX":     "Success",
+	"3XX":     "Redirection",
+	"4XX":     "ClientError",
+	"5XX":     "ServerError",
+	"DEFAULT": "Unexpected",
+}
+
+// parseResponse returns the returnType of an operation as a string. That is the type of the best matching response,
+// or a union of all documented responses if Options.ResultUnions is set. It is returned with the media types the
+// returned responses are accepted as
+func (c *converter) parseResponse(oasOperation openapi3.Operation, operationName string, kind oasOperationKind, url string) (string, []string, error) {
+	if c.opts.ResultUnions {
+		return c.resultUnion(oasOperation, operationName)
+	}
+
+	// since we can only take one for GraphQL, the selector has to figure out which is the best
+	var selector ResponseSelector = SuccessResponseSelector{}
+	if c.opts.ResponseSelector != nil {
+		selector = c.opts.ResponseSelector
+	}
+	bestMatch := selector.SelectResponse(string(kind), url, &oasOperation)
+	if bestMatch == "" {
+		log.Warnf("%s %s - no response selected, the operation returns no content", kind, url)
+		typeName, err := c.noContentType(operationName, "", nil)
+		return typeName, nil, err
+	}
+	oasResponse := oasOperation.Responses[bestMatch]
+	if oasResponse == nil {
+		return "", nil, fmt.Errorf("selected response %s is not documented", bestMatch)
+	}
+	// anonymous response schemas are named after the operation, e.g. getUser -> GetUserResponse
+	typeName, mediaType, err := c.responseType(oasResponse, bestMatch, upperFirst(operationName)+"Response")
+	if err != nil {
+		return "", nil, err
+	}
+	if typeName == "" {
+		typeName, err = c.noContentType(operationName, bestMatch, oasResponse.Value)
+		return typeName, nil, err
+	}
+	if c.opts.ResponsePayloads {
+		typeName, err = c.payloadType(upperFirst(operationName)+"Payload", operationName, bestMatch, typeName, oasResponse.Value)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if mediaType == "" {
+		return typeName, nil, nil
+	}
+	return typeName, []string{mediaType}, nil
+}
+
+// responseType returns the GraphQL type of a single response and the media type it was generated from,
+// or empty strings if it has no content. Anonymous schemas are declared as baseName, unless the response is
+// shared via components.responses, then they are named after the component
+func (c *converter) responseType(oasResponse *openapi3.ResponseRef, code string, baseName string) (string, string, error) {
+	if oasResponse.Value == nil {
+		return "", "", fmt.Errorf("unresolved response %s", oasResponse.Ref)
+	}
+	if componentName, ok := componentRef(oasResponse.Ref, "responses"); ok {
+		baseName = upperFirst(toTypeName(componentName))
+	}
+
+	// check for no content
+	content := oasResponse.Value.Content
+	if len(content) == 0 {
+		return "", "", nil
+	}
+
+	// check for json, or whatever is preferred
+	if mediaType, preferred := c.preferredMediaType(content); preferred != nil {
+		// if it is a named reference, we take it, else it is an anonymous type named after the response
+		typeName, err := c.schemaRefConversion(preferred.Schema, baseName)
+		if err != nil {
+			return "", "", err
+		}
+		return typeName, mediaType, nil
+	}
+	if len(c.opts.MediaTypes) > 0 {
+		log.Warnf("%s response has no schema in any of %s", code, strings.Join(c.opts.MediaTypes, ", "))
+	}
+
+	// if we have a simple plain text, we go with string
+	for _, mediaType := range util.SortedKeys(content) {
+		if mediaTypeMatches("text/plain", mediaType) {
+			if isMediaRange(mediaType) {
+				mediaType = "text/plain"
+			}
+			return string(gqlString), mediaType, nil
+		}
+	}
+
+	// todo check for xml or something else, but I don't think that is in scope rn
+	log.Warnf("%s response has no supported content format, defaulting to String", code)
+	return string(gqlString), "", nil
+}
+
+// resultUnion returns a union of all documented responses of an operation, e.g. GetUserResult = User | NotFoundError.
+// Error responses become synthetic error types carrying the status code, so clients can tell them apart.
+// Successful responses that are no object, e.g. a list, or have no content are wrapped in a payload type,
+// since GraphQL unions can only hold objects. With Options.ResponsePayloads all successful responses are wrapped
+func (c *converter) resultUnion(oasOperation openapi3.Operation, operationName string) (string, []string, error) {
+	members := make([]string, 0)
+	mediaTypes := make([]string, 0)
+	codes := responseCodes(oasOperation.Responses)
+	hasErrors := false
+	for _, code := range codes {
+		hasErrors = hasErrors || !strings.HasPrefix(code, "2")
+	}
+	// without errors, responses without content are returned as configured, instead of being a union member
+	noContentCode := ""
+	var noContent *openapi3.Response
+	add := func(names ...string) {
+		for _, name := range names {
+			if !util.IsInSlice(name, members) {
+				members = append(members, name)
+			}
+		}
+	}
+
+	for _, code := range codes {
+		typeName, mediaType, err := c.responseType(oasOperation.Responses[code], code, upperFirst(operationName)+statusName(code)+"Response")
+		if err != nil {
+			return "", nil, err
+		}
+		if mediaType != "" && !util.IsInSlice(mediaType, mediaTypes) {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+
+		if !strings.HasPrefix(code, "2") {
+			add(c.errorType(code, typeName))
+			continue
+		}
+		switch {
+		case typeName == "" && !hasErrors:
+			if noContentCode == "" {
+				noContentCode, noContent = code, oasOperation.Responses[code].Value
+			}
+		case c.symbols.types[typeName] == symbolType && !c.opts.ResponsePayloads:
+			add(typeName)
+		case c.symbols.types[typeName] == symbolUnion && c.union(typeName) != nil && !c.opts.ResponsePayloads:
+			// unions can't be nested, but their members can be ours
+			add(c.union(typeName).Members...)
+		default:
+			payloadName, err := c.payloadType(upperFirst(operationName)+statusName(code)+"Result", operationName, code, typeName, oasOperation.Responses[code].Value)
+			if err != nil {
+				return "", nil, err
+			}
+			add(payloadName)
+		}
+	}
+
+	if len(members) == 0 {
+		typeName, err := c.noContentType(operationName, noContentCode, noContent)
+		return typeName, mediaTypes, err
+	}
+	if len(members) == 1 && !hasErrors {
+		// without any documented error, the union would just be the successful type
+		return members[0], mediaTypes, nil
+	}
+	name := c.symbols.declareType(upperFirst(operationName)+"Result", symbolUnion, operationName)
+	c.addUnion(GqlUnion{
+		Name:        name,
+		Description: fmt.Sprintf("All documented results of %s", operationName),
+		Members:     members,
+	})
+	return name, mediaTypes, nil
+}
+
+// errorType declares the synthetic type of an error response and returns its name, e.g. NotFoundError { status, body }.
+// Error types are shared by all operations with the same status and body
+func (c *converter) errorType(code string, bodyType string) string {
+	key := code + " " + bodyType
+	if name, ok := c.errorTypes[key]; ok {
+		return name
+	}
+
+	status := statusName(code)
+	baseName := status
+	if !strings.HasSuffix(baseName, "Error") {
+		baseName += "Error"
+	}
+	// the same status with another body, e.g. a different problem schema, gets a type of its own
+	if c.symbols.isTypeTaken(baseName) && bodyType != "" {
+		baseName = strings.TrimSuffix(baseName, "Error") + toTypeName(bodyType) + "Error"
+	}
+	name := c.symbols.declareType(baseName, symbolType, "responses "+code)
+	c.errorTypes[key] = name
+
+	attributes := []GqlAttribute{statusAttribute()}
+	if bodyType != "" {
+		attributes = append(attributes, GqlAttribute{Name: "body", Description: "The body of the response", Type: bodyType})
+	}
+	c.addType(GqlType{
+		Name:        name,
+		Description: fmt.Sprintf("The API responded with %s", statusDescription(code)),
+		Type:        "object",
+		Attributes:  attributes,
+	})
+	return name
+}
+
+func statusAttribute() GqlAttribute {
+	return GqlAttribute{Name: statusField, Description: "The HTTP status code of the response", Type: string(gqlInt), IsRequired: true}
+}
+
+// union returns the declared union with the given name, or nil if there is none
+func (c *converter) union(name string) *GqlUnion {
+	for idx := range c.unions {
+		if c.unions[idx].Name == name {
+			return &c.unions[idx]
+		}
+	}
+	return nil
+}
+
+// responseCodes returns the status codes of all responses, single codes first, then ranges, e.g. 2XX, then default
+func responseCodes(responses openapi3.Responses) []string {
+	codes := util.SortedKeys(responses)
+	rank := func(code string) int {
+		if _, err := strconv.Atoi(code); err == nil {
+			return 0
+		}
+		if strings.EqualFold(code, "default") {
+			return 2
+		}
+		return 1
+	}
+	sort.SliceStable(codes, func(i, j int) bool {
+		return rank(codes[i]) < rank(codes[j])
+	})
+	return codes
+}
+
+// statusName names a status code as part of a type name, e.g. "404" -> NotFound, "5XX" -> ServerError
+func statusName(code string) string {
+	if name, ok := rangeStatusNames[strings.ToUpper(code)]; ok {
+		return name
+	}
+	if status, err := strconv.Atoi(code); err == nil && http.StatusText(status) != "" {
+		return toTypeName(http.StatusText(status))
+	}
+	return "Status" + toTypeName(code)
+}
+
+// statusDescription describes a status code for humans, e.g. "404" -> 404 Not Found
+func statusDescription(code string) string {
+	if status, err := strconv.Atoi(code); err == nil && http.StatusText(status) != "" {
+		return fmt.Sprintf("%d %s", status, http.StatusText(status))
+	}
+	if strings.EqualFold(code, "default") {
+		return "an undocumented status"
+	}
+	return code
+}
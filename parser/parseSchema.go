@@ -2,74 +2,139 @@ package parser
 
 import (
 	"fmt"
+	"github.com/FrauElster/gopenApiToGraphQL/util"
 	"github.com/getkin/kin-openapi/openapi3"
-	"path/filepath"
+	log "github.com/sirupsen/logrus"
 )
 
 // parseSchema converts OpenAPI schemas to GraphQL types
-func parseSchema(doc *openapi3.T) ([]GqlType, error) {
+func (c *converter) parseSchema() error {
 	// we differentiate between
 	// 		- "named" types: 	basically all schemas that are explicitly named,
 	// 		- anonymous types: 	everything else, where the schema author just put the schema in line
 
+	// named types are declared before any anonymous type can take their name. In case they collide with each other
+	// or a built-in, the first one wins, which is the first in alphabetical or declaration order
+	componentNames := util.FilterSlice(c.componentOrder(), func(componentName string) bool {
+		// GraphQL has no named lists, so arrays are inlined wherever they are referenced, e.g. Pets -> [Pet!]
+		return !isArraySchema(c.flattenAllOf(c.doc.Components.Schemas[componentName].Value))
+	})
+	// enums and unions are converted once per schema, so a component that is just a reference to another enum or union
+	// component is no declaration on its own, but another name for it, e.g. ColorAlias -> Color
+	aliases := make(map[string]string)
+	for _, componentName := range componentNames {
+		schema := c.doc.Components.Schemas[componentName]
+		kind := namedSymbolKind(c.flattenAllOf(schema.Value))
+		if target, ok := componentRef(schema.Ref, "schemas"); ok && (kind == symbolEnum || kind == symbolUnion) && util.IsInSlice(target, componentNames) {
+			aliases[componentName] = target
+			continue
+		}
+		c.componentNames[componentName] = c.symbols.declareType(toTypeName(componentName), kind, componentPointer("schemas", componentName))
+		// a component that is just a reference to another one is a type on its own, but the schema belongs to the other one
+		if schema.Ref == "" {
+			c.componentSchemas[schema.Value] = componentName
+		}
+	}
+
+	for alias, target := range aliases {
+		// aliases may point to aliases themselves
+		for hops := 0; hops < len(aliases); hops++ {
+			next, isAlias := aliases[target]
+			if !isAlias {
+				break
+			}
+			target = next
+		}
+		if name, ok := c.componentNames[target]; ok {
+			c.componentNames[alias] = name
+		}
+	}
+
 	// now all OpenAPI schemas are per definition named types, here we just map them
-	gqlTypes := make([]GqlType, 0, len(doc.Components.Schemas))
-	for name, schema := range doc.Components.Schemas {
-		gqlType, err := namedTypeConversion(name, schema.Value)
+	for _, componentName := range componentNames {
+		if _, isAlias := aliases[componentName]; isAlias {
+			continue
+		}
+		schema := c.doc.Components.Schemas[componentName]
+		name := c.componentNames[componentName]
+		// enums are no types, they are declared on their own
+		if isEnumSchema(schema.Value) {
+			c.enumConversion(schema.Value, name, true)
+			continue
+		}
+		// as are unions
+		if isUnionSchema(schema.Value) {
+			_, err := c.unionConversion(schema.Value, name, true)
+			if err != nil {
+				return fmt.Errorf("could not parse schema: %w", err)
+			}
+			continue
+		}
+
+		gqlType, err := c.namedTypeConversion(name, schema.Value)
 		if err != nil {
-			return gqlTypes, fmt.Errorf("could not parse schema: %w", err)
+			return fmt.Errorf("could not parse schema: %w", err)
 		}
-		gqlTypes = append(gqlTypes, gqlType)
+		c.addType(gqlType)
 	}
 
-	return gqlTypes, nil
+	return nil
+}
+
+func isArraySchema(schema *openapi3.Schema) bool {
+	return schema.Type == "array"
+}
+
+// namedSymbolKind returns what a named schema will be declared as
+func namedSymbolKind(schema *openapi3.Schema) symbolKind {
+	switch {
+	case isEnumSchema(schema):
+		return symbolEnum
+	case isUnionSchema(schema):
+		return symbolUnion
+	case isObjectSchema(schema):
+		return symbolType
+	default:
+		return symbolScalar
+	}
 }
 
-func namedTypeConversion(name string, schema *openapi3.Schema) (GqlType, error) {
+func (c *converter) namedTypeConversion(name string, schema *openapi3.Schema) (GqlType, error) {
+	// types extending others via allOf implement their interfaces
+	interfaces, err := c.allOfInterfaces(schema)
+	if err != nil {
+		return GqlType{}, err
+	}
+	schema = c.flattenAllOf(schema)
+
 	switch schema.Type {
 	case "object":
 		// so objects are kind of tricky, because we have to map each property
-		attributes := make([]GqlAttribute, 0)
-		for propertyName, property := range schema.Properties {
-			// so if it is a ref, e.g. '#/components/schema/User', know we will have that component as GraphQL type
-			// therefore we can just set the Component Name as type
-			if property.Ref != "" {
-				attributes = append(attributes, GqlAttribute{Name: propertyName, Type: filepath.Base(property.Ref)})
-				continue
-			}
-
-			// okay know we have to figure out what type it is, we know it is not a reference to a component, so it is an
-			// "anonymous" type. The magic happens in the anonymousTypeConversion
-			typeName, err := anonymousTypeConversion(property.Value)
-			if err != nil {
-				return GqlType{}, err
-			}
-			attributes = append(attributes, GqlAttribute{Name: propertyName, Type: typeName})
+		attributes, err := c.attributesConversion(name, schema)
+		if err != nil {
+			return GqlType{}, err
 		}
 
 		return GqlType{
-			Name:       name,
-			Type:       schema.Type,
-			Attributes: attributes,
+			Name:        name,
+			Description: schema.Description,
+			Type:        schema.Type,
+			Attributes:  attributes,
+			Interfaces:  interfaces,
 		}, nil
 	case "array":
 		// actually I don't know if this can even happen, but I am too lazy to check the specs
-		var typeName string
-		if schema.Items.Ref != "" {
-			typeName = filepath.Base(schema.Items.Ref)
-		} else {
-			var err error
-			typeName, err = anonymousTypeConversion(schema.Items.Value)
-			if err != nil {
-				return GqlType{}, err
-			}
+		typeName, err := c.schemaRefConversion(schema.Items, name+"Item")
+		if err != nil {
+			return GqlType{}, err
 		}
-		typeName = fmt.Sprintf("[%s]", typeName)
+		typeName = listOf(typeName, schema.Items)
 
 		return GqlType{
-			Name:       name,
-			Type:       typeName,
-			Attributes: []GqlAttribute{},
+			Name:        name,
+			Description: schema.Description,
+			Type:        typeName,
+			Attributes:  []GqlAttribute{},
 		}, nil
 	default:
 		// if it is neither a struct nor an array, it has to be a OpenAPI BaseType, e.g. "number", "integer", or "string"
@@ -84,70 +149,152 @@ func namedTypeConversion(name string, schema *openapi3.Schema) (GqlType, error)
 		}
 
 		return GqlType{
-			Name:       name,
-			Type:       string(typeName),
-			Attributes: []GqlAttribute{},
+			Name:        name,
+			Description: schema.Description,
+			Type:        string(typeName),
+			Attributes:  []GqlAttribute{},
 		}, nil
 	}
 }
 
-func anonymousTypeConversion(schema *openapi3.Schema) (string, error) {
-	// fixme add hints
-	switch schema.Type {
-	case "object":
-		// again if it is an object, we have to check the types of its properties, ...that screams recursion
-
-		attributes := make([]GqlAttribute, 0)
-		for propertyName, property := range schema.Properties {
-			// like in namedTypeConversion, if we have a reference to an OpenAPI component,
-			// we can just use its name as type
-			if property.Ref != "" {
-				attributes = append(attributes, GqlAttribute{Name: propertyName, Type: filepath.Base(property.Ref),
-					IsRequired: !property.Value.Nullable})
-				continue
-			}
+// attributesConversion maps the properties of an object schema to GqlAttribute s
+func (c *converter) attributesConversion(typeName string, schema *openapi3.Schema) ([]GqlAttribute, error) {
+	attributes := make([]GqlAttribute, 0, len(schema.Properties))
+	taken := make(map[string]bool)
+	for _, propertyName := range c.propertyNames(schema) {
+		property := schema.Properties[propertyName]
+		// property names may be anything in OpenAPI, e.g. "@type", so they have to be sanitized
+		name := c.symbols.declareAttribute(taken, toFieldName(propertyName), propertyName, typeName+"."+propertyName)
 
-			// else we will recursively check its type with this function again, someday, somewhere this all has to be a BaseType
-			typeName, err := anonymousTypeConversion(property.Value)
-			if err != nil {
-				return "", err
-			}
-			attributes = append(attributes, GqlAttribute{
-				Name: propertyName, Type: typeName, IsRequired: !property.Value.Nullable})
+		// so if it is a ref, e.g. '#/components/schema/User', know we will have that component as GraphQL type
+		// therefore we can just set the Component Name as type.
+		// Otherwise, we have to figure out what type it is. It is an "anonymous" type, which we will name after
+		// its parent, e.g. User.address -> UserAddress
+		propertyType, err := c.schemaRefConversion(property, typeName+upperFirst(name))
+		if err != nil {
+			return nil, err
 		}
+		attributes = append(attributes, GqlAttribute{
+			Name:        name,
+			WireName:    propertyName,
+			Description: propertyDescription(property),
+			Type:        propertyType,
+			IsRequired:  isRequiredProperty(schema, propertyName, property),
+			Directives:  deprecation(property.Value.Deprecated, property.Value.ExtensionProps, property.Value.Description),
+		})
+	}
+	return attributes, nil
+}
 
-		// because anonymous types won't be declared explicitly, we map it to a string right here, as if it was a simple
-		// "String" type
-		result := ""
-		for idx := range attributes {
-			result += fmt.Sprintf("%s: %s", attributes[idx].Name, attributes[idx].Type)
-			if attributes[idx].IsRequired {
-				result += "!"
-			}
-			result += "\n"
+// isRequiredProperty reports whether a property is non-null in GraphQL, which means it has to be listed in the
+// required properties of its parent and must not be nullable
+func isRequiredProperty(parent *openapi3.Schema, propertyName string, property *openapi3.SchemaRef) bool {
+	return util.IsInSlice(propertyName, parent.Required) && !property.Value.Nullable
+}
+
+// propertyDescription returns the description of a property. References are described by the type they refer to,
+// so we don't want to repeat it on every property
+func propertyDescription(property *openapi3.SchemaRef) string {
+	if property.Ref != "" {
+		return ""
+	}
+	return property.Value.Description
+}
+
+// listOf wraps the item type in a GraphQL list. Items are non-null, unless they are nullable
+func listOf(itemType string, items *openapi3.SchemaRef) string {
+	if items.Value.Nullable {
+		return fmt.Sprintf("[%s]", itemType)
+	}
+	return fmt.Sprintf("[%s!]", itemType)
+}
+
+// schemaRefConversion returns the GraphQL type of a schema that might be a reference to a component.
+// baseName is the name an anonymous object will be declared with
+func (c *converter) schemaRefConversion(schemaRef *openapi3.SchemaRef, baseName string) (string, error) {
+	schemaRef = aliasedRef(schemaRef)
+	// a reference to a component, we will have that component as GraphQL type, so we use its name
+	if name, ok := c.namedSchema(schemaRef); ok {
+		return name, nil
+	}
+	// anything else is converted in place, references to parts of other schemas are named after where they point to
+	return c.anonymousTypeConversion(schemaRef.Value, c.refBaseName(schemaRef.Ref, baseName))
+}
+
+func (c *converter) anonymousTypeConversion(schema *openapi3.Schema, baseName string) (string, error) {
+	interfaces, err := c.allOfInterfaces(schema)
+	if err != nil {
+		return "", err
+	}
+	schema = c.flattenAllOf(schema)
+
+	// enums are named after their parent like objects, e.g. Pet.status -> PetStatus
+	if isEnumSchema(schema) {
+		return c.enumConversion(schema, baseName, false), nil
+	}
+	// so are unions
+	if isUnionSchema(schema) {
+		return c.unionConversion(schema, baseName, false)
+	}
+
+	switch schema.Type {
+	case "object":
+		// GraphQL has no anonymous types, so we have to declare it as type on its own.
+		// The same schema might be reachable in multiple ways (e.g. a shared response), we only want it once
+		if typeName, ok := c.hoisted[schema]; ok {
+			return typeName, nil
 		}
+		if len(schema.Properties) == 0 {
+			// a free form object, there is nothing we can map its properties to
+			log.Warnf("%s - object without properties, defaulting to %s", baseName, gqlString)
+			return string(gqlString), nil
+		}
+		typeName := c.symbols.declareType(baseName, symbolType, baseName)
+		c.hoisted[schema] = typeName
 
-		return fmt.Sprintf("{%s}", result), nil
-	case "array":
-		// again if we have a component reference, we can use it and only have to wrap it with []
-		if schema.Items.Ref != "" {
-			typeName := filepath.Base(schema.Items.Ref)
-			return fmt.Sprintf("[%s]", typeName), nil
+		// again if it is an object, we have to check the types of its properties, ...that screams recursion
+		attributes, err := c.attributesConversion(typeName, schema)
+		if err != nil {
+			return "", err
 		}
 
+		c.addType(GqlType{
+			Name:        typeName,
+			Description: schema.Description,
+			Type:        schema.Type,
+			Attributes:  attributes,
+			Interfaces:  interfaces,
+		})
+		return typeName, nil
+	case "array":
+		// again if we have a component reference, we can use it and only have to wrap it with [],
 		// else we will have to get the type of the items
-		typeName, err := anonymousTypeConversion(schema.Items.Value)
+		typeName, err := c.schemaRefConversion(schema.Items, baseName+"Item")
 		if err != nil {
 			return "", err
 		}
-		return fmt.Sprintf("[%s]", typeName), nil
+		return listOf(typeName, schema.Items), nil
 
 	default:
 		// it is a base type! yay, that's a root of the recursion tree :)
-		typeName, err := baseTypeConversion(oasBaseType(schema.Type))
-		if err != nil {
-			return "", err
+		// its format might tell us a more specific scalar, e.g. a "date-time" string is a DateTime
+		return c.scalarConversion(schema)
+	}
+}
+
+// scalarConversion returns the GraphQL scalar for an OpenAPI base type, respecting the configured format mapping.
+// Custom scalars are declared on first use
+func (c *converter) scalarConversion(schema *openapi3.Schema) (string, error) {
+	if scalarName, ok := c.opts.Scalars[schema.Format]; ok && schema.Format != "" {
+		if isBuiltInScalar(scalarName) {
+			return scalarName, nil
 		}
-		return string(typeName), nil
+		return c.useScalar(scalarName), nil
+	}
+
+	typeName, err := baseTypeConversion(oasBaseType(schema.Type))
+	if err != nil {
+		return "", err
 	}
+	return string(typeName), nil
 }
@@ -0,0 +1,155 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/FrauElster/gopenApiToGraphQL/util"
+	"github.com/getkin/kin-openapi/openapi3"
+	log "github.com/sirupsen/logrus"
+)
+
+// gqlJSON is the scalar we fall back to for everything GraphQL has no proper representation for
+const gqlJSON = "JSON"
+
+// isUnionSchema reports whether a schema is a choice between alternatives, which is what GraphQL calls a union
+func isUnionSchema(schema *openapi3.Schema) bool {
+	return len(schema.OneOf) > 0 || len(schema.AnyOf) > 0
+}
+
+// isObjectSchema reports whether a schema ends up as a GraphQL object type, allOf compositions are merged into one
+func isObjectSchema(schema *openapi3.Schema) bool {
+	if isEnumSchema(schema) || isUnionSchema(schema) {
+		return false
+	}
+	return (schema.Type == "object" && len(schema.Properties) > 0) || len(schema.AllOf) > 0
+}
+
+// unionAlternatives returns the alternatives of a union schema, oneOf and anyOf are the same thing for us
+func unionAlternatives(schema *openapi3.Schema) openapi3.SchemaRefs {
+	if len(schema.OneOf) > 0 {
+		return schema.OneOf
+	}
+	return schema.AnyOf
+}
+
+// isUnionExpressible reports whether GraphQL can represent the union, which is only the case if all members are objects.
+// Nested unions are fine, since we flatten them
+func isUnionExpressible(schema *openapi3.Schema) bool {
+	for _, alternative := range unionAlternatives(schema) {
+		if isUnionSchema(alternative.Value) {
+			if !isUnionExpressible(alternative.Value) {
+				return false
+			}
+			continue
+		}
+		if !isObjectSchema(alternative.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// unionConversion declares a GraphQL union for a oneOf / anyOf schema and returns its name.
+// Named unions keep the component name, anonymous ones get the given name.
+// Unions of anything else than objects can't be expressed in GraphQL, they become scalars
+func (c *converter) unionConversion(schema *openapi3.Schema, name string, isNamed bool) (string, error) {
+	if unionName, ok := c.unionNames[schema]; ok {
+		return unionName, nil
+	}
+
+	if !isUnionExpressible(schema) {
+		// named unions stay named, so all references to them are still valid
+		scalarName := gqlJSON
+		if isNamed {
+			scalarName = name
+		}
+		log.Warnf("%s - union of non-object types is not supported by GraphQL, using scalar %s", name, scalarName)
+		if !isNamed {
+			scalarName = c.useScalar(scalarName)
+		} else {
+			// named ones are already declared by parseSchema
+			c.addScalar(scalarName)
+		}
+		c.unionNames[schema] = scalarName
+		return scalarName, nil
+	}
+
+	if !isNamed {
+		name = c.symbols.declareType(name, symbolUnion, name)
+	}
+	c.unionNames[schema] = name
+
+	members, err := c.unionMembers(schema, name)
+	if err != nil {
+		return "", fmt.Errorf("could not convert union %s: %w", name, err)
+	}
+
+	c.addUnion(GqlUnion{
+		Name:          name,
+		Description:   schema.Description,
+		Members:       members,
+		Discriminator: c.discriminatorConversion(schema),
+	})
+	return name, nil
+}
+
+// unionMembers returns the GraphQL type names of all alternatives, flattening nested unions
+func (c *converter) unionMembers(schema *openapi3.Schema, unionName string) ([]string, error) {
+	members := make([]string, 0)
+	for idx, alternative := range unionAlternatives(schema) {
+		var memberNames []string
+		if isUnionSchema(alternative.Value) {
+			// GraphQL unions can only contain objects, so the members of nested unions become our members
+			var err error
+			memberNames, err = c.unionMembers(alternative.Value, unionName)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// anonymous members are named after their title, or their position if they have none
+			baseName := fmt.Sprintf("%sOption%d", unionName, idx+1)
+			if alternative.Value.Title != "" {
+				baseName = upperFirst(toCamelCase(alternative.Value.Title))
+			}
+			memberName, err := c.schemaRefConversion(alternative, baseName)
+			if err != nil {
+				return nil, err
+			}
+			memberNames = []string{memberName}
+		}
+
+		// the same type could be in there twice, which GraphQL does not like
+		for _, memberName := range memberNames {
+			if !util.IsInSlice(memberName, members) {
+				members = append(members, memberName)
+			}
+		}
+	}
+	return members, nil
+}
+
+// discriminatorConversion maps the discriminator values to the GraphQL type they resolve to.
+// If no explicit mapping is given, OpenAPI uses the schema names as values
+func (c *converter) discriminatorConversion(schema *openapi3.Schema) *GqlDiscriminator {
+	if schema.Discriminator == nil {
+		return nil
+	}
+
+	mapping := make(map[string]string)
+	if len(schema.Discriminator.Mapping) == 0 {
+		for _, alternative := range unionAlternatives(schema) {
+			// the schema name is sent over the wire, which is not necessarily the sanitized GraphQL name
+			if componentName, ok := componentRef(alternative.Ref, "schemas"); ok {
+				mapping[componentName] = c.refName(alternative.Ref)
+			}
+		}
+	}
+	for value, ref := range schema.Discriminator.Mapping {
+		// mappings are either references, e.g. '#/components/schemas/Dog', or plain schema names
+		mapping[value] = c.refName(ref)
+	}
+
+	return &GqlDiscriminator{
+		PropertyName: schema.Discriminator.PropertyName,
+		Mapping:      mapping,
+	}
+}
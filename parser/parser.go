@@ -4,22 +4,39 @@ import (
 	"context"
 	"fmt"
 	"github.com/FrauElster/gopenApiToGraphQL/util"
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+	log "github.com/sirupsen/logrus"
 	"io"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 )
 
+// Parse converts the OpenAPI spec at oasFile, which is either a path or a URL, to a GraphQL spec using the DefaultOptions
 func Parse(oasFile string) (GqlSpec, error) {
+	return ParseWithOptions(oasFile, DefaultOptions())
+}
+
+// ParseWithOptions converts the OpenAPI spec at oasFile, which is either a path or a URL, to a GraphQL spec
+func ParseWithOptions(oasFile string, opts Options) (GqlSpec, error) {
 	// get the data either from download or reading file
 	oasSpec, err := getOas(context.Background(), oasFile)
 	if err != nil {
 		return GqlSpec{}, err
 	}
 
+	// OpenAPI 3.1 is rewritten to 3.0 first, which is what kin-openapi understands
+	oasSpec, err = normalizeOas31(oasSpec)
+	if err != nil {
+		return GqlSpec{}, err
+	}
+
 	// parse the file to OAS representation
-	doc, err := openapi3.NewLoader().LoadFromData(oasSpec)
+	doc, err := loadOas(oasSpec)
 	if err != nil {
 		return GqlSpec{}, fmt.Errorf("could not parse OAS: %s", err)
 	}
@@ -29,60 +46,135 @@ func Parse(oasFile string) (GqlSpec, error) {
 	}
 
 	// parse types
-	gqlTypes, err := parseSchema(doc)
+	conv := newConverter(doc, opts)
+	if opts.Ordering == OrderBySource {
+		conv.order, err = readSourceOrder(oasSpec, doc)
+		if err != nil {
+			return GqlSpec{}, err
+		}
+	}
+	err = conv.parseSchema()
 	if err != nil {
 		return GqlSpec{}, err
 	}
 
-	// actually scalars are only types without attributes, we have to separate them
+	queries := make([]GqlOperation, 0)
+	mutations := make([]GqlOperation, 0)
+	for _, url := range conv.pathOrder() {
+		for _, pathOp := range conv.pathOperations(url) {
+			// the options decide whether it is a query or mutation, e.g. GET -> query, PATCH -> mutation
+			switch opts.OperationRoots[string(pathOp.kind)] {
+			case RootQuery:
+				query, err := conv.parseOperation(*pathOp.operation, url, pathOp.kind)
+				if err != nil {
+					return GqlSpec{}, fmt.Errorf("could not parse query: %w", err)
+				}
+				queries = append(queries, query)
+			case RootMutation:
+				mutation, err := conv.parseOperation(*pathOp.operation, url, pathOp.kind)
+				if err != nil {
+					return GqlSpec{}, fmt.Errorf("could not parse mutation: %w", err)
+				}
+				mutations = append(mutations, mutation)
+			default:
+				log.Debugf("%s %s - skipping operation", pathOp.kind, url)
+			}
+		}
+	}
+
+	// now that we know all types, base types can implement their interfaces
+	conv.linkInterfaces()
+
+	// actually scalars are only types without attributes, we have to separate them.
+	// Equally named scalars are the same thing, e.g. a component DateTime and the scalar used for format date-time,
+	// so each is declared once, with the description of the component if there is one
 	gqlScalars := make([]GqlScalar, 0)
-	gqlTypes = util.FilterSlice(gqlTypes, func(t GqlType) bool {
+	addScalar := func(scalar GqlScalar) {
+		for idx := range gqlScalars {
+			if gqlScalars[idx].Name == scalar.Name {
+				if gqlScalars[idx].Description == "" {
+					gqlScalars[idx].Description = scalar.Description
+				}
+				return
+			}
+		}
+		gqlScalars = append(gqlScalars, scalar)
+	}
+	gqlTypes := util.FilterSlice(conv.types, func(t GqlType) bool {
 		if len(t.Attributes) == 0 {
-			gqlScalars = append(gqlScalars, GqlScalar{Name: t.Name})
+			addScalar(GqlScalar{Name: t.Name, Description: t.Description})
 			return false
 		}
 		return true
 	})
+	for _, scalar := range conv.scalars {
+		addScalar(GqlScalar{Name: scalar})
+	}
 
-	queries := make([]GqlOperation, 0)
-	mutations := make([]GqlOperation, 0)
-	for url, path := range doc.Paths {
-		if path.Get != nil {
-			query, err := parseOperation(*path.Get, url, oasGet)
-			if err != nil {
-				return GqlSpec{}, fmt.Errorf("could not parse query: %w", err)
-			}
-			queries = append(queries, query)
-		}
-		if path.Delete != nil {
-			mutation, err := parseOperation(*path.Delete, url, oasDelete)
-			if err != nil {
-				return GqlSpec{}, fmt.Errorf("could not parse mutation: %w", err)
-			}
-			mutations = append(mutations, mutation)
-		}
-		if path.Post != nil {
-			mutation, err := parseOperation(*path.Post, url, oasPost)
-			if err != nil {
-				return GqlSpec{}, fmt.Errorf("could not parse mutation: %w", err)
+	generationTime := time.Time{}
+	if opts.Timestamp {
+		generationTime = time.Now()
+	}
+
+	return GqlSpec{
+		GenerationTime: generationTime,
+		Types:          gqlTypes,
+		Inputs:         conv.collectInputs(),
+		Enums:          conv.enums,
+		Unions:         conv.unions,
+		Interfaces:     conv.interfaces,
+		Diagnostics:    conv.symbols.diagnostics,
+		Mutations:      mutations,
+		Scalars:        gqlScalars,
+		Queries:        queries,
+	}, nil
+}
+
+// loadOas parses an OpenAPI 3 document. Swagger 2.0 documents are converted to OpenAPI 3 first,
+// which moves definitions to components and body and formData parameters to request bodies
+func loadOas(data []byte) (*openapi3.T, error) {
+	if !isSwagger(data) {
+		return openapi3.NewLoader().LoadFromData(data)
+	}
+
+	var swagger openapi2.T
+	err := yaml.Unmarshal(data, &swagger)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := openapi2conv.ToV3(&swagger)
+	if err != nil {
+		return nil, err
+	}
+	requireFormBodies(doc)
+	return doc, nil
+}
+
+// requireFormBodies marks request bodies converted from formData parameters as required if any of the parameters is.
+// The conversion keeps the required parameters in the form schema, but not on the body itself
+func requireFormBodies(doc *openapi3.T) {
+	for _, pathItem := range doc.Paths {
+		for _, operation := range pathItem.Operations() {
+			if operation.RequestBody == nil || operation.RequestBody.Value == nil || operation.RequestBody.Value.Required {
+				continue
 			}
-			mutations = append(mutations, mutation)
-		}
-		if path.Put != nil {
-			mutation, err := parseOperation(*path.Put, url, oasPut)
-			if err != nil {
-				return GqlSpec{}, fmt.Errorf("could not parse mutation: %w", err)
+			for _, mime := range []string{"application/x-www-form-urlencoded", "multipart/form-data"} {
+				formContent := operation.RequestBody.Value.Content.Get(mime)
+				if formContent != nil && formContent.Schema != nil && formContent.Schema.Value != nil && len(formContent.Schema.Value.Required) > 0 {
+					operation.RequestBody.Value.Required = true
+				}
 			}
-			mutations = append(mutations, mutation)
 		}
 	}
+}
 
-	return GqlSpec{
-		Types:     gqlTypes,
-		Mutations: mutations,
-		Scalars:   gqlScalars,
-		Queries:   queries,
-	}, nil
+// isSwagger reports whether data is a Swagger 2.0 document, which declares 'swagger: "2.0"' instead of 'openapi: 3.x.x'
+func isSwagger(data []byte) bool {
+	version := struct {
+		Swagger string `json:"swagger"`
+	}{}
+	err := yaml.Unmarshal(data, &version)
+	return err == nil && strings.HasPrefix(version.Swagger, "2")
 }
 
 // getOas downloads the spec to a file if identifier is a web address or checks if the file exists and uniforms it to absolute path
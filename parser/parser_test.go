@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"testing"
+)
+
+// deterministicSpec touches everything kept in maps by kin-openapi or the converter, e.g. paths, methods,
+// properties, response codes, headers, discriminator mappings and interfaces
+const deterministicSpec = `openapi: 3.0.3
+info: {title: deterministic, version: "1"}
+paths:
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      operationId: getPet
+      parameters:
+        - {name: X-Tenant, in: header, schema: {type: string}}
+        - {name: verbose, in: query, schema: {type: boolean}}
+      responses:
+        "200":
+          description: ok
+          headers:
+            X-Rate-Limit: {schema: {type: integer}}
+            ETag: {schema: {type: string}}
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+        "404":
+          description: missing
+          content:
+            application/problem+json:
+              schema: {$ref: '#/components/schemas/Problem'}
+        default: {description: unexpected}
+    delete:
+      operationId: deletePet
+      responses:
+        "204": {description: deleted, headers: {X-Request-Id: {schema: {type: string}}}}
+        "409": {description: conflict}
+  /pets:
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Cat'}
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema: {type: object, properties: {id: {type: string}, createdAt: {type: string, format: date-time}}}
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: ok
+          headers:
+            X-Total-Count: {schema: {type: integer}}
+            Link: {schema: {type: string}}
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+components:
+  schemas:
+    Problem: {type: object, properties: {title: {type: string}, status: {type: integer}, detail: {type: string}}}
+    Animal:
+      type: object
+      discriminator: {propertyName: kind}
+      properties: {name: {type: string}, kind: {type: string}, age: {type: integer}}
+    Cat:
+      allOf:
+        - $ref: '#/components/schemas/Animal'
+        - {type: object, properties: {meow: {type: string}, color: {type: string, enum: [black, white, red]}}}
+    Dog:
+      allOf:
+        - $ref: '#/components/schemas/Animal'
+        - {type: object, properties: {bark: {type: string}, size: {type: string, enum: [small, large]}}}
+    Pet:
+      oneOf: [{$ref: '#/components/schemas/Cat'}, {$ref: '#/components/schemas/Dog'}]
+      discriminator:
+        propertyName: kind
+        mapping: {cat: '#/components/schemas/Cat', dog: '#/components/schemas/Dog'}
+`
+
+func TestOutputIsDeterministic(t *testing.T) {
+	for _, ordering := range []Ordering{OrderByName, OrderBySource} {
+		opts := DefaultOptions()
+		opts.Ordering = ordering
+		opts.GenerateInterfaces = true
+		opts.ResultUnions = true
+		opts.ResponsePayloads = true
+
+		spec, _ := parseSpec(t, deterministicSpec, opts)
+		want := spec.String()
+		for run := 0; run < 10; run++ {
+			spec, _ := parseSpec(t, deterministicSpec, opts)
+			if got := spec.String(); got != want {
+				t.Fatalf("ordering by %s, run %d differs from the first one:\n%s\nfirst run:\n%s", ordering, run, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"github.com/FrauElster/gopenApiToGraphQL/util"
+	"github.com/getkin/kin-openapi/openapi3"
+	"path"
+	"strings"
+)
+
+// componentPointer returns the JSON pointer to a component, e.g. ("schemas", "Pet") -> '#/components/schemas/Pet'
+func componentPointer(kind string, name string) string {
+	return "#/components/" + kind + "/" + encodeRefToken(name)
+}
+
+// refTokens returns the decoded tokens of the JSON pointer in a reference, e.g. 'common.yaml#/components/schemas/Pet'
+// -> [components schemas Pet]. References without pointer, e.g. a whole file, have no tokens
+func refTokens(ref string) []string {
+	_, pointer, found := strings.Cut(ref, "#")
+	if !found || strings.Trim(pointer, "/") == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for idx := range tokens {
+		tokens[idx] = decodeRefToken(tokens[idx])
+	}
+	return tokens
+}
+
+// componentRef returns the name of the component of the given kind (e.g. "schemas" or "parameters") a reference
+// points to. Pointers into a component, e.g. '#/components/schemas/Pet/properties/tag', are no component references
+func componentRef(ref string, kind string) (string, bool) {
+	tokens := refTokens(ref)
+	if len(tokens) != 3 || tokens[0] != "components" || tokens[1] != kind {
+		return "", false
+	}
+	return tokens[2], true
+}
+
+// namedSchema returns the name of the named type a schema resolves to. That is the case for references to
+// components.schemas, but also for any other reference that ends up at one, e.g. a reference to a component
+// parameter's schema, which itself references a component schema
+func (c *converter) namedSchema(schemaRef *openapi3.SchemaRef) (string, bool) {
+	if componentName, ok := componentRef(schemaRef.Ref, "schemas"); ok {
+		if name, ok := c.componentNames[componentName]; ok {
+			return name, true
+		}
+	}
+	// kin-openapi resolves every reference to the schema it points to, so we can identify components by their schema
+	if componentName, ok := c.componentSchemas[schemaRef.Value]; ok && schemaRef.Ref != "" {
+		return c.componentNames[componentName], true
+	}
+	return "", false
+}
+
+// refName returns the GraphQL name of the component a reference points to, e.g. '#/components/schemas/Pet' -> Pet,
+// '#/components/schemas/user-profile' -> UserProfile. It is meant for references we only know as string,
+// e.g. in a discriminator mapping, where we have no schema we could convert
+func (c *converter) refName(ref string) string {
+	componentName, ok := componentRef(ref, "schemas")
+	if !ok {
+		// discriminator mappings may be plain schema names as well
+		componentName = decodeRefToken(path.Base(ref))
+	}
+	if name, ok := c.componentNames[componentName]; ok {
+		return name
+	}
+	return toTypeName(componentName)
+}
+
+// structuralTokens are the parts of a JSON pointer that only describe the structure of the document,
+// they are left out when we name something after a pointer
+var structuralTokens = []string{"properties", "schema", "allOf", "oneOf", "anyOf", "not", "additionalProperties"}
+
+// refBaseName returns the name an anonymous schema we reached via reference is declared with.
+// Schemas inside a component are named after their location, e.g. '#/components/schemas/Pet/properties/tag' -> PetTag,
+// which is the name the schema gets when Pet is converted anyway. Everything else keeps the fallback
+func (c *converter) refBaseName(ref string, fallback string) string {
+	tokens := refTokens(ref)
+	if len(tokens) < 3 || tokens[0] != "components" {
+		return fallback
+	}
+
+	name := toTypeName(tokens[2])
+	if tokens[1] == "schemas" {
+		name = c.refName(componentPointer("schemas", tokens[2]))
+	}
+	for idx := 3; idx < len(tokens); idx++ {
+		switch {
+		case tokens[idx] == "items":
+			name += "Item"
+		case tokens[idx] == "content":
+			// the media type, e.g. 'content/application~1json/schema', is no name either
+			idx++
+		case !util.IsInSlice(tokens[idx], structuralTokens) && !isIndexToken(tokens[idx]):
+			name += upperFirst(toFieldName(tokens[idx]))
+		}
+	}
+	return name
+}
+
+// isIndexToken reports whether a token is an array index, e.g. the 0 in 'allOf/0'
+func isIndexToken(token string) bool {
+	return token != "" && strings.Trim(token, "0123456789") == ""
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"strconv"
+	"strings"
+)
+
+// ResponseSelector picks the response an operation returns, since a GraphQL field has a single type.
+// It is not used with Options.ResultUnions, where every documented response is returned
+type ResponseSelector interface {
+	// SelectResponse returns the status code of the chosen response, e.g. "200", "2XX" or "default",
+	// or an empty string if none of the responses should be returned. method and path identify the operation
+	SelectResponse(method string, path string, operation *openapi3.Operation) string
+}
+
+// ResponseSelectorFunc lets ordinary functions be used as ResponseSelector
+type ResponseSelectorFunc func(method string, path string, operation *openapi3.Operation) string
+
+func (f ResponseSelectorFunc) SelectResponse(method string, path string, operation *openapi3.Operation) string {
+	return f(method, path, operation)
+}
+
+// SuccessResponseSelector is the ResponseSelector used by DefaultOptions. It picks the lowest successful status code,
+// e.g. 200 before 201, then the 2XX range and finally the default response. Error responses are never picked
+type SuccessResponseSelector struct{}
+
+func (SuccessResponseSelector) SelectResponse(_ string, _ string, operation *openapi3.Operation) string {
+	codes := responseCodes(operation.Responses)
+	for _, code := range codes {
+		if status, err := strconv.Atoi(code); err == nil && status >= 200 && status < 300 {
+			return code
+		}
+	}
+	for _, code := range codes {
+		if strings.EqualFold(code, "2XX") {
+			return code
+		}
+	}
+	for _, code := range codes {
+		if strings.EqualFold(code, "default") {
+			return code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"testing"
+)
+
+func TestSuccessResponseSelector(t *testing.T) {
+	tests := []struct {
+		codes []string
+		want  string
+	}{
+		{[]string{"default", "200"}, "200"},
+		{[]string{"201", "200"}, "200"},
+		{[]string{"404", "204", "default"}, "204"},
+		{[]string{"2XX", "default"}, "2XX"},
+		{[]string{"404", "default"}, "default"},
+		{[]string{"404"}, ""},
+		{[]string{}, ""},
+	}
+	for _, tt := range tests {
+		operation := &openapi3.Operation{Responses: openapi3.Responses{}}
+		for _, code := range tt.codes {
+			operation.Responses[code] = &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription(code)}
+		}
+		if got := (SuccessResponseSelector{}).SelectResponse("GET", "/pets", operation); got != tt.want {
+			t.Errorf("SelectResponse(%v) = %q, want %q", tt.codes, got, tt.want)
+		}
+	}
+}
+
+const selectorSpec = `openapi: 3.0.3
+info: {title: selector, version: "1"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+        default:
+          description: error
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Problem'}
+components:
+  schemas:
+    Pet: {type: object, properties: {name: {type: string}}}
+    Problem: {type: object, properties: {title: {type: string}}}
+`
+
+func TestResponseSelectorOverride(t *testing.T) {
+	_, schema := parseSpec(t, selectorSpec, DefaultOptions())
+	if got := schema.Query.Fields.ForName("listPets").Type.String(); got != "[Pet!]" {
+		t.Errorf("listPets returns %s, want [Pet!]", got)
+	}
+
+	opts := DefaultOptions()
+	selected := make([]string, 0)
+	opts.ResponseSelector = ResponseSelectorFunc(func(method string, path string, operation *openapi3.Operation) string {
+		selected = append(selected, method+" "+path)
+		return "default"
+	})
+	_, schema = parseSpec(t, selectorSpec, opts)
+	if got := schema.Query.Fields.ForName("listPets").Type.String(); got != "Problem" {
+		t.Errorf("listPets returns %s, want the selected Problem", got)
+	}
+	if len(selected) != 1 || selected[0] != "GET /pets" {
+		t.Errorf("selector was called for %v, want GET /pets", selected)
+	}
+}
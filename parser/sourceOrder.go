@@ -0,0 +1,246 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// sourceOrder is the order paths, operations, schemas and properties are declared in the OpenAPI document.
+// kin-openapi keeps all of them in maps, so we read the document a second time and walk it next to the loaded one
+type sourceOrder struct {
+	paths   []string
+	methods map[string][]oasOperationKind
+	schemas []string
+	// properties maps every schema declared in the document to the order of its properties
+	properties map[*openapi3.Schema][]string
+	// headers maps every response declared in the document to the order of its headers
+	headers map[*openapi3.Response][]string
+}
+
+// readSourceOrder reads the declaration order of data, which is the document doc was loaded from.
+// YAML is a superset of JSON, so this works for both
+func readSourceOrder(data []byte, doc *openapi3.T) (*sourceOrder, error) {
+	root := yaml.Node{}
+	err := yaml.Unmarshal(data, &root)
+	if err != nil {
+		return nil, fmt.Errorf("could not read declaration order: %w", err)
+	}
+
+	order := &sourceOrder{
+		paths:      make([]string, 0),
+		methods:    make(map[string][]oasOperationKind),
+		schemas:    make([]string, 0),
+		properties: make(map[*openapi3.Schema][]string),
+		headers:    make(map[*openapi3.Response][]string),
+	}
+	if len(root.Content) == 0 {
+		return order, nil
+	}
+	document := root.Content[0]
+
+	pathsNode := mappingValue(document, "paths")
+	for _, url := range mappingKeys(pathsNode) {
+		order.paths = append(order.paths, url)
+		if pathItem := doc.Paths[url]; pathItem != nil {
+			order.walkPathItem(url, mappingValue(pathsNode, url), pathItem)
+		}
+	}
+
+	componentsNode := mappingValue(document, "components")
+	schemasNode := mappingValue(componentsNode, "schemas")
+	if mappingValue(document, "swagger") != nil {
+		// Swagger 2.0 has no components, its schemas are called definitions
+		componentsNode = document
+		schemasNode = mappingValue(document, "definitions")
+	}
+	for _, name := range mappingKeys(schemasNode) {
+		order.schemas = append(order.schemas, name)
+		order.walkSchema(mappingValue(schemasNode, name), doc.Components.Schemas[name])
+	}
+	parametersNode := mappingValue(componentsNode, "parameters")
+	for _, name := range mappingKeys(parametersNode) {
+		order.walkParameter(mappingValue(parametersNode, name), doc.Components.Parameters[name])
+	}
+	requestBodiesNode := mappingValue(componentsNode, "requestBodies")
+	for _, name := range mappingKeys(requestBodiesNode) {
+		if requestBody := doc.Components.RequestBodies[name]; requestBody != nil && requestBody.Value != nil {
+			order.walkContent(mappingValue(mappingValue(requestBodiesNode, name), "content"), requestBody.Value.Content)
+		}
+	}
+	responsesNode := mappingValue(componentsNode, "responses")
+	for _, name := range mappingKeys(responsesNode) {
+		order.walkResponse(mappingValue(responsesNode, name), doc.Components.Responses[name])
+	}
+
+	return order, nil
+}
+
+func (o *sourceOrder) walkPathItem(url string, node *yaml.Node, pathItem *openapi3.PathItem) {
+	methods := make([]oasOperationKind, 0)
+	operations := pathItem.Operations()
+	for _, key := range mappingKeys(node) {
+		operation := operations[strings.ToUpper(key)]
+		if operation == nil {
+			// not an operation, e.g. "parameters" or "summary"
+			continue
+		}
+		methods = append(methods, oasOperationKind(strings.ToUpper(key)))
+		o.walkOperation(mappingValue(node, key), operation)
+	}
+	o.methods[url] = methods
+
+	o.walkParameters(mappingValue(node, "parameters"), pathItem.Parameters, nil)
+}
+
+func (o *sourceOrder) walkOperation(node *yaml.Node, operation *openapi3.Operation) {
+	o.walkParameters(mappingValue(node, "parameters"), operation.Parameters, operation.RequestBody)
+
+	requestBodyNode := mappingValue(node, "requestBody")
+	if operation.RequestBody != nil && operation.RequestBody.Value != nil && !isRefNode(requestBodyNode) {
+		o.walkContent(mappingValue(requestBodyNode, "content"), operation.RequestBody.Value.Content)
+	}
+
+	responsesNode := mappingValue(node, "responses")
+	for _, code := range mappingKeys(responsesNode) {
+		o.walkResponse(mappingValue(responsesNode, code), operation.Responses[code])
+	}
+}
+
+// walkParameters walks a list of parameters. They are matched by name and location instead of their index,
+// since Swagger 2.0 body parameters are no parameters anymore, but the request body
+func (o *sourceOrder) walkParameters(node *yaml.Node, parameters openapi3.Parameters, requestBody *openapi3.RequestBodyRef) {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, parameterNode := range node.Content {
+		name, in := scalarValue(mappingValue(parameterNode, "name")), scalarValue(mappingValue(parameterNode, "in"))
+		if in == "body" && requestBody != nil && requestBody.Value != nil {
+			for _, mediaType := range requestBody.Value.Content {
+				o.walkSchema(mappingValue(parameterNode, "schema"), mediaType.Schema)
+			}
+			continue
+		}
+		for _, parameter := range parameters {
+			if parameter.Value != nil && parameter.Value.Name == name && parameter.Value.In == in {
+				o.walkParameter(parameterNode, parameter)
+			}
+		}
+	}
+}
+
+func (o *sourceOrder) walkParameter(node *yaml.Node, parameter *openapi3.ParameterRef) {
+	if parameter == nil || parameter.Value == nil || isRefNode(node) {
+		return
+	}
+	o.walkSchema(mappingValue(node, "schema"), parameter.Value.Schema)
+	o.walkContent(mappingValue(node, "content"), parameter.Value.Content)
+}
+
+func (o *sourceOrder) walkResponse(node *yaml.Node, response *openapi3.ResponseRef) {
+	if response == nil || response.Value == nil || isRefNode(node) {
+		return
+	}
+	o.walkContent(mappingValue(node, "content"), response.Value.Content)
+	headersNode := mappingValue(node, "headers")
+	o.headers[response.Value] = mappingKeys(headersNode)
+	for _, name := range mappingKeys(headersNode) {
+		if header := response.Value.Headers[name]; header != nil && header.Value != nil && !isRefNode(mappingValue(headersNode, name)) {
+			o.walkSchema(mappingValue(mappingValue(headersNode, name), "schema"), header.Value.Schema)
+		}
+	}
+	// Swagger 2.0 responses have a single schema for all media types
+	if schemaNode := mappingValue(node, "schema"); schemaNode != nil {
+		for _, mediaType := range response.Value.Content {
+			o.walkSchema(schemaNode, mediaType.Schema)
+		}
+	}
+}
+
+func (o *sourceOrder) walkContent(node *yaml.Node, content openapi3.Content) {
+	for _, mime := range mappingKeys(node) {
+		if mediaType := content[mime]; mediaType != nil {
+			o.walkSchema(mappingValue(mappingValue(node, mime), "schema"), mediaType.Schema)
+		}
+	}
+}
+
+// walkSchema records the property order of a schema and all schemas nested in it.
+// References are skipped, the schemas they point to are walked where they are declared
+func (o *sourceOrder) walkSchema(node *yaml.Node, schemaRef *openapi3.SchemaRef) {
+	if node == nil || schemaRef == nil || schemaRef.Value == nil || isRefNode(node) {
+		return
+	}
+	schema := schemaRef.Value
+
+	propertiesNode := mappingValue(node, "properties")
+	if propertiesNode != nil {
+		o.properties[schema] = mappingKeys(propertiesNode)
+		for _, propertyName := range o.properties[schema] {
+			o.walkSchema(mappingValue(propertiesNode, propertyName), schema.Properties[propertyName])
+		}
+	}
+	o.walkSchema(mappingValue(node, "items"), schema.Items)
+	o.walkSchema(mappingValue(node, "not"), schema.Not)
+	o.walkSchema(mappingValue(node, "additionalProperties"), schema.AdditionalProperties)
+
+	composed := map[string]openapi3.SchemaRefs{"allOf": schema.AllOf, "oneOf": schema.OneOf, "anyOf": schema.AnyOf}
+	for key, parts := range composed {
+		partsNode := mappingValue(node, key)
+		if partsNode == nil || partsNode.Kind != yaml.SequenceNode {
+			continue
+		}
+		for idx, partNode := range partsNode.Content {
+			if idx < len(parts) {
+				o.walkSchema(partNode, parts[idx])
+			}
+		}
+	}
+}
+
+// mappingKeys returns the keys of a YAML mapping in the order they are written in
+func mappingKeys(node *yaml.Node) []string {
+	node = resolveAlias(node)
+	keys := make([]string, 0)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return keys
+	}
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		keys = append(keys, node.Content[idx].Value)
+	}
+	return keys
+}
+
+// mappingValue returns the value of key in a YAML mapping, or nil if there is none
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		if node.Content[idx].Value == key {
+			return resolveAlias(node.Content[idx+1])
+		}
+	}
+	return nil
+}
+
+func scalarValue(node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	return node.Value
+}
+
+func isRefNode(node *yaml.Node) bool {
+	return mappingValue(node, "$ref") != nil
+}
+
+// resolveAlias follows YAML aliases, e.g. '*pet', to the node they are anchored at
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// symbolKind is what a name in the GraphQL schema is used for
+type symbolKind string
+
+const (
+	symbolBuiltIn   symbolKind = "built-in"
+	symbolType      symbolKind = "type"
+	symbolInput     symbolKind = "input"
+	symbolEnum      symbolKind = "enum"
+	symbolScalar    symbolKind = "scalar"
+	symbolUnion     symbolKind = "union"
+	symbolInterface symbolKind = "interface"
+	symbolField     symbolKind = "field"
+)
+
+// renameSuffixes are tried first to resolve a collision, before we fall back to numbering,
+// e.g. a schema named "Query" becomes "QueryType"
+var renameSuffixes = map[symbolKind]string{
+	symbolType:      "Type",
+	symbolInput:     "Input",
+	symbolEnum:      "Enum",
+	symbolScalar:    "Scalar",
+	symbolUnion:     "Union",
+	symbolInterface: "Interface",
+}
+
+// builtInNames can never be declared, since GraphQL itself uses them
+var builtInNames = []string{
+	string(gqlString), string(gqlInt), string(gqlFloat), string(gqlBoolean), string(gqlID),
+	"Query", "Mutation", "Subscription",
+}
+
+// symbolTable keeps track of every name in the GraphQL schema and resolves collisions.
+// Types, inputs, enums, scalars, unions and interfaces share one namespace, the root fields another one.
+// Collisions are resolved deterministically by whoever comes first keeping the name, every rename is reported
+type symbolTable struct {
+	types       map[string]symbolKind
+	fields      map[string]bool
+	diagnostics []GqlDiagnostic
+}
+
+func newSymbolTable() *symbolTable {
+	table := &symbolTable{
+		types:       make(map[string]symbolKind),
+		fields:      make(map[string]bool),
+		diagnostics: make([]GqlDiagnostic, 0),
+	}
+	for _, name := range builtInNames {
+		table.types[name] = symbolBuiltIn
+	}
+	return table
+}
+
+// declareType reserves a name in the type namespace and returns it, renamed if it was already taken.
+// Scalars may share a name, since two equally named scalars are the same thing
+func (t *symbolTable) declareType(name string, kind symbolKind, origin string) string {
+	existing, taken := t.types[name]
+	if !taken || (kind == symbolScalar && existing == symbolScalar) {
+		t.types[name] = kind
+		return name
+	}
+
+	// first we try to make clear what it is, e.g. Query -> QueryType, if that does not work we number it
+	unique := ""
+	if suffix := renameSuffixes[kind]; !strings.HasSuffix(name, suffix) && !t.isTypeTaken(name+suffix) {
+		unique = name + suffix
+	}
+	for idx := 2; unique == ""; idx++ {
+		if candidate := fmt.Sprintf("%s%d", name, idx); !t.isTypeTaken(candidate) {
+			unique = candidate
+		}
+	}
+	t.types[unique] = kind
+	t.rename(origin, fmt.Sprintf("%s %s renamed to %s, the name is already used by a %s", kind, name, unique, existing))
+	return unique
+}
+
+func (t *symbolTable) isTypeTaken(name string) bool {
+	_, taken := t.types[name]
+	return taken
+}
+
+// declareField reserves a root field name and returns it, renamed if it was already taken.
+// Query and Mutation fields share the resolver namespace in most servers, so they have to be unique across both
+func (t *symbolTable) declareField(name string, origin string) string {
+	unique := name
+	for idx := 2; t.fields[unique]; idx++ {
+		unique = fmt.Sprintf("%s%d", name, idx)
+	}
+	t.fields[unique] = true
+	if unique != name {
+		t.rename(origin, fmt.Sprintf("%s %s renamed to %s, the name is already used by another operation", symbolField, name, unique))
+	}
+	return unique
+}
+
+// declareAttribute returns a unique name for an attribute within a single type or input, taken holds the names
+// already used in it. Different wire names may end up with the same GraphQL name, e.g. "user-name" and "userName"
+func (t *symbolTable) declareAttribute(taken map[string]bool, name string, wireName string, origin string) string {
+	unique := name
+	for idx := 2; taken[unique]; idx++ {
+		unique = fmt.Sprintf("%s%d", name, idx)
+	}
+	taken[unique] = true
+	if unique != name {
+		t.rename(origin, fmt.Sprintf("attribute %s renamed to %s, the name is already used by another attribute", wireName, unique))
+	}
+	return unique
+}
+
+func (t *symbolTable) rename(origin string, message string) {
+	t.diagnostics = append(t.diagnostics, GqlDiagnostic{Origin: origin, Message: message})
+}
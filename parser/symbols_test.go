@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeclareType(t *testing.T) {
+	table := newSymbolTable()
+	tests := []struct {
+		name string
+		kind symbolKind
+		want string
+	}{
+		{"Pet", symbolType, "Pet"},
+		// the first one keeps the name, the others are suffixed and then numbered
+		{"Pet", symbolInput, "PetInput"},
+		{"Pet", symbolInput, "Pet2"},
+		{"Query", symbolType, "QueryType"},
+		{"String", symbolScalar, "StringScalar"},
+		// equally named scalars are the same thing
+		{"DateTime", symbolScalar, "DateTime"},
+		{"DateTime", symbolScalar, "DateTime"},
+		{"DateTime", symbolType, "DateTimeType"},
+	}
+	for _, tt := range tests {
+		if got := table.declareType(tt.name, tt.kind, tt.name); got != tt.want {
+			t.Errorf("declareType(%q, %s) = %q, want %q", tt.name, tt.kind, got, tt.want)
+		}
+	}
+	if len(table.diagnostics) != 5 {
+		t.Errorf("got %d diagnostics, want one for each of the 5 renames: %v", len(table.diagnostics), table.diagnostics)
+	}
+}
+
+func TestDeclareField(t *testing.T) {
+	table := newSymbolTable()
+	for _, want := range []string{"getPet", "getPet2", "getPet3"} {
+		if got := table.declareField("getPet", "GET /pets"); got != want {
+			t.Errorf("declareField(getPet) = %q, want %q", got, want)
+		}
+	}
+	if len(table.diagnostics) != 2 {
+		t.Errorf("got %d diagnostics, want 2: %v", len(table.diagnostics), table.diagnostics)
+	}
+}
+
+func TestDeclareAttribute(t *testing.T) {
+	table := newSymbolTable()
+	taken := map[string]bool{statusField: true}
+	if got := table.declareAttribute(taken, "userName", "user-name", "User"); got != "userName" {
+		t.Errorf("declareAttribute(userName) = %q, want userName", got)
+	}
+	if got := table.declareAttribute(taken, "userName", "user_name", "User"); got != "userName2" {
+		t.Errorf("declareAttribute(userName) = %q, want userName2", got)
+	}
+	if got := table.declareAttribute(taken, statusField, statusField, "User"); got != "status2" {
+		t.Errorf("declareAttribute(status) = %q, want status2", got)
+	}
+	if len(table.diagnostics) != 2 {
+		t.Errorf("got %d diagnostics, want 2: %v", len(table.diagnostics), table.diagnostics)
+	}
+}
+
+// parseSpec converts an OpenAPI document and makes sure the rendered GraphQL spec is valid
+func parseSpec(t *testing.T, document string, opts Options) (GqlSpec, *ast.Schema) {
+	t.Helper()
+	oasFile := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(oasFile, []byte(document), 0644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := ParseWithOptions(oasFile, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sdl := spec.String()
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: sdl})
+	if gqlErr != nil {
+		t.Fatalf("invalid GraphQL spec: %s\n%s", gqlErr, sdl)
+	}
+	return spec, schema
+}
+
+// collisionSpec uses the same names in many ways, which all have to end up in a valid GraphQL spec
+const collisionSpec = `openapi: 3.0.3
+info: {title: collisions, version: "1"}
+paths:
+  /things:
+    get:
+      operationId: getThing
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Thing'}
+    delete:
+      operationId: get-thing
+      responses:
+        "204": {description: deleted}
+components:
+  schemas:
+    UUID: {type: string, description: a unique id}
+    JSON: {type: string}
+    DateTime: {type: string, format: date-time}
+    Color: {type: string, enum: [red, green]}
+    ColorAlias: {$ref: '#/components/schemas/Color'}
+    State: {type: string, enum: [in-progress, IN_PROGRESS]}
+    Cat: {type: object, properties: {meow: {type: string}}}
+    Dog: {type: object, properties: {bark: {type: string}}}
+    Pet: {oneOf: [{$ref: '#/components/schemas/Cat'}, {$ref: '#/components/schemas/Dog'}]}
+    PetAlias: {$ref: '#/components/schemas/Pet'}
+    Query: {type: object, properties: {q: {type: string}}}
+    Thing:
+      type: object
+      properties:
+        id: {type: string, format: uuid}
+        any: {oneOf: [{type: string}, {type: integer}]}
+        createdAt: {type: string, format: date-time}
+        updatedAt: {$ref: '#/components/schemas/DateTime'}
+        color: {$ref: '#/components/schemas/ColorAlias'}
+        state: {$ref: '#/components/schemas/State'}
+        pet: {$ref: '#/components/schemas/PetAlias'}
+        query: {$ref: '#/components/schemas/Query'}
+`
+
+func TestCollisionsResultInValidSpec(t *testing.T) {
+	for _, ordering := range []Ordering{OrderByName, OrderBySource} {
+		opts := DefaultOptions()
+		opts.Ordering = ordering
+		spec, schema := parseSpec(t, collisionSpec, opts)
+
+		// scalars are declared once, with the description of the component
+		if schema.Types["UUID"].Description != "a unique id" {
+			t.Errorf("UUID has description %q", schema.Types["UUID"].Description)
+		}
+		// aliases of enums and unions are the aliased type
+		thing := schema.Types["Thing"]
+		for field, want := range map[string]string{"color": "Color", "pet": "Pet", "query": "QueryType"} {
+			if got := thing.Fields.ForName(field).Type.Name(); got != want {
+				t.Errorf("Thing.%s has type %s, want %s", field, got, want)
+			}
+		}
+
+		// every rename is reported
+		for _, renamed := range []string{"Query", "IN_PROGRESS", "getThing2"} {
+			found := false
+			for _, diagnostic := range spec.Diagnostics {
+				found = found || strings.Contains(diagnostic.String(), renamed)
+			}
+			if !found {
+				t.Errorf("no diagnostic for %s in %v", renamed, spec.Diagnostics)
+			}
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package util
 
+import "sort"
+
 func IsInSlice[T comparable](obj T, slice []T) bool {
 	for _, it := range slice {
 		if it == obj {
@@ -18,3 +20,13 @@ func FilterSlice[T any](slice []T, decider func(T) bool) []T {
 	}
 	return result
 }
+
+// SortedKeys returns the keys of a map in ascending order, ranging over the map directly has a random order
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}